@@ -2,58 +2,300 @@ package cyoa
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// CLIFlow
-func CLIFlow(s Story, c string) {
-	scanner := bufio.NewScanner(os.Stdin)
+// DefaultSaveFile is the file CLIFlow keeps save slots in unless WithSaveFile is used.
+const DefaultSaveFile = "cyoa-saves.json"
+
+// step is a chapter the player has been in, with their state on arriving.
+type step struct {
+	Chapter string `json:"chapter"`
+	State   State  `json:"state"`
+}
+
+// saveSlot is a game saved in a named slot.
+type saveSlot struct {
+	History []step    `json:"history"`
+	Saved   time.Time `json:"saved"`
+}
+
+// cli plays a story in the terminal.
+type cli struct {
+	story    Story
+	in       *bufio.Scanner
+	out      io.Writer
+	saveFile string
+	history  []step // Chapters visited, the current one last
+}
+
+// CLIOption represents a functional option for configuring CLIFlow.
+type CLIOption func(c *cli)
+
+// WithInput returns a CLIOption that reads the player's commands from r instead of stdin.
+func WithInput(r io.Reader) CLIOption {
+	return func(c *cli) {
+		c.in = bufio.NewScanner(r)
+	}
+}
+
+// WithOutput returns a CLIOption that writes the story to w instead of stdout.
+func WithOutput(w io.Writer) CLIOption {
+	return func(c *cli) {
+		c.out = w
+	}
+}
+
+// WithSaveFile returns a CLIOption that keeps the save slots in the JSON file at path.
+func WithSaveFile(path string) CLIOption {
+	return func(c *cli) {
+		c.saveFile = path
+	}
+}
+
+// CLIFlow plays the story in the terminal, starting at chapter c, and tracks the
+// player's state along the way. Besides choosing an option by number, the player can
+// type "b" to go back a chapter, "h" to show the chapters visited, "save <slot>" and
+// "load <slot>" to save and resume games, "load" to list the slots, and "q" to quit.
+//
+// Example usage:
+//
+//	CLIFlow(story, IntroChapter, WithSaveFile("story.saves.json"))
+func CLIFlow(s Story, c string, opts ...CLIOption) {
+	cl := &cli{
+		story:    s,
+		in:       bufio.NewScanner(os.Stdin),
+		out:      os.Stdout,
+		saveFile: DefaultSaveFile,
+	}
+	// Apply each option to configure the flow
+	for _, opt := range opts {
+		opt(cl)
+	}
+
+	var st State
+	s[c].Enter(&st)
+	cl.history = []step{{Chapter: c, State: st}}
+	cl.run()
+}
+
+// run shows the current chapter and carries out the player's commands until they quit.
+func (cl *cli) run() {
+	show := true
+	for {
+		cur := cl.history[len(cl.history)-1]
+		choices := cl.story[cur.Chapter].Choices(cur.State)
+		if show {
+			cl.show(cur, choices)
+			show = false
+		}
+
+		// Add a line break before the input prompt
+		if len(choices) == 0 {
+			fmt.Fprint(cl.out, "\nType 'b' to go back, 'load <slot>' to resume a game or 'q' to quit: ")
+		} else {
+			fmt.Fprint(cl.out, "\nChoose an option ('b' back, 'h' history, 'save'/'load' <slot>, 'q' quit): ")
+		}
+
+		// Get user input
+		if !cl.in.Scan() {
+			return
+		}
+		fields := strings.Fields(cl.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		cmd, slot := strings.ToLower(fields[0]), strings.Join(fields[1:], " ")
+
+		switch cmd {
+		case "q":
+			// Allow the user to quit by typing "q"
+			fmt.Fprintln(cl.out, "Exiting the story. Thanks for playing!")
+			return
+		case "b":
+			if len(cl.history) == 1 {
+				fmt.Fprintln(cl.out, "You are at the start of your journey.")
+				continue
+			}
+			cl.history = cl.history[:len(cl.history)-1]
+			show = true
+		case "h":
+			cl.showHistory()
+		case "save":
+			if slot == "" {
+				fmt.Fprintln(cl.out, "Please name the slot to save to, e.g. 'save before-the-storm'.")
+			} else if err := cl.save(slot); err != nil {
+				fmt.Fprintf(cl.out, "Failed to save the game: %v\n", err)
+			} else {
+				fmt.Fprintf(cl.out, "Game saved to slot %q.\n", slot)
+			}
+		case "load":
+			if slot == "" {
+				cl.listSlots()
+			} else if err := cl.load(slot); err != nil {
+				fmt.Fprintf(cl.out, "Failed to load the game: %v\n", err)
+			} else {
+				show = true
+			}
+		default:
+			// Convert user input to integer
+			choice, err := strconv.Atoi(cmd)
+			if err != nil || choice < 1 || choice > len(choices) {
+				fmt.Fprintln(cl.out, "Invalid choice. Please enter a valid number or command.")
+				continue
+			}
+
+			// Follow the option to the next chapter
+			st := cur.State.clone()
+			next, err := cl.story.Choose(&st, cur.Chapter, choices[choice-1].Index)
+			if err != nil {
+				fmt.Fprintln(cl.out, err)
+				continue
+			}
+			cl.history = append(cl.history, step{Chapter: next, State: st})
+			show = true
+		}
+	}
+}
+
+// show prints the chapter of the step and the options available to the player.
+func (cl *cli) show(cur step, choices []Choice) {
+	ch := cl.story[cur.Chapter]
 
 	// Print the title
-	fmt.Printf("\n======= %v =======\n\n", s[c].Title)
+	fmt.Fprintf(cl.out, "\n======= %v =======\n\n", ch.Title)
 
 	// Print the story content
-	fmt.Printf("%v\n", strings.Join(s[c].Story, "\n    "))
-	fmt.Print("\n-----------------------------\n")
+	fmt.Fprintf(cl.out, "%v\n", strings.Join(ch.Story, "\n    "))
+	fmt.Fprint(cl.out, "\n-----------------------------\n")
+
+	// Print what the player has gathered so far
+	if desc := cur.State.String(); desc != "" {
+		fmt.Fprintf(cl.out, "(%s)\n\n", desc)
+	}
 
-	// If there are no options, end the story
-	if len(s[c].Options) == 0 {
-		fmt.Println("The End. Thanks for playing!")
+	// If there are no options left, end the story
+	if len(choices) == 0 {
+		fmt.Fprintln(cl.out, "The End. Thanks for playing!")
 		return
 	}
 
 	// Print the options
-	fmt.Println("What would you like to do next?")
-	for i, opt := range s[c].Options {
-		fmt.Printf("  %d) %s\n", i+1, opt.Text)
+	fmt.Fprintln(cl.out, "What would you like to do next?")
+	for i, opt := range choices {
+		fmt.Fprintf(cl.out, "  %d) %s\n", i+1, opt.Text)
 	}
+}
 
-	// Add a line break before the input prompt
-	fmt.Print("\nChoose an option (or 'q' to quit): ")
+// showHistory prints the chapters visited so far.
+func (cl *cli) showHistory() {
+	fmt.Fprintln(cl.out, "Your path so far:")
+	for i, st := range cl.history {
+		fmt.Fprintf(cl.out, "  %d. %s\n", i+1, cl.story[st.Chapter].Title)
+	}
+}
 
-	// Get user input
-	if scanner.Scan() {
-		co := scanner.Text()
+// readSlots reads the save slots from the save file, which may not exist yet.
+func (cl *cli) readSlots() (map[string]saveSlot, error) {
+	slots := make(map[string]saveSlot)
+	data, err := os.ReadFile(cl.saveFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return slots, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &slots); err != nil {
+		return nil, fmt.Errorf("invalid save file %s: %v", cl.saveFile, err)
+	}
+	return slots, nil
+}
 
-		// Allow the user to quit by typing "q"
-		if strings.ToLower(co) == "q" {
-			fmt.Println("Exiting the story. Thanks for playing!")
-			return
-		}
+// save saves the game to the named slot, replacing any game saved there.
+func (cl *cli) save(slot string) error {
+	slots, err := cl.readSlots()
+	if err != nil {
+		return err
+	}
+	slots[slot] = saveSlot{History: cl.history, Saved: time.Now()}
+	data, err := json.MarshalIndent(slots, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cl.saveFile, data)
+}
 
-		// Convert user input to integer
-		choice, err := strconv.Atoi(co)
-		if err != nil || choice < 1 || choice > len(s[c].Options) {
-			fmt.Println("Invalid choice. Please enter a valid number.")
-			CLIFlow(s, c) // Restart the current chapter
-			return
+// writeFileAtomic replaces the file at path with data. It writes a temporary file and
+// renames it, so a crash or a full disk never leaves the saved games half written.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// load resumes the game saved in the named slot.
+func (cl *cli) load(slot string) error {
+	slots, err := cl.readSlots()
+	if err != nil {
+		return err
+	}
+	saved, ok := slots[slot]
+	if !ok || len(saved.History) == 0 {
+		return fmt.Errorf("no game saved in slot %q", slot)
+	}
+	for _, st := range saved.History {
+		if _, ok := cl.story[st.Chapter]; !ok {
+			return fmt.Errorf("slot %q was saved from another story", slot)
 		}
+	}
+	cl.history = saved.History
+	return nil
+}
 
-		// Recursively call CLIFlow with the next chapter
-		nextChapter := s[c].Options[choice-1].NextArc
-		CLIFlow(s, nextChapter)
+// listSlots prints the save slots, most recent first.
+func (cl *cli) listSlots() {
+	slots, err := cl.readSlots()
+	if err != nil {
+		fmt.Fprintf(cl.out, "Failed to read the saved games: %v\n", err)
+		return
+	}
+	if len(slots) == 0 {
+		fmt.Fprintln(cl.out, "There are no saved games yet. Type 'save <slot>' to save one.")
+		return
+	}
+	names := make([]string, 0, len(slots))
+	for name := range slots {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return slots[names[i]].Saved.After(slots[names[j]].Saved)
+	})
+	fmt.Fprintln(cl.out, "Saved games:")
+	for _, name := range names {
+		saved := slots[name]
+		if len(saved.History) == 0 {
+			continue
+		}
+		cur := saved.History[len(saved.History)-1]
+		fmt.Fprintf(cl.out, "  %s - %s (%s)\n", name, cl.story[cur.Chapter].Title, saved.Saved.Format("2006-01-02 15:04"))
 	}
 }
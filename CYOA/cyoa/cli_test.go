@@ -0,0 +1,58 @@
+package cyoa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLIFlow(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Crossroads", Options: []Option{
+			{Text: "Go left", NextArc: "left", Set: []string{"steps += 1"}},
+			{Text: "Go right", NextArc: "right"},
+		}},
+		"left":  {Title: "Left", Options: []Option{{Text: "Walk on", NextArc: "end"}}},
+		"right": {Title: "Right"},
+		"end":   {Title: "The End"},
+	}
+	saves := filepath.Join(t.TempDir(), "saves.json")
+	play := func(input string) string {
+		var out strings.Builder
+		CLIFlow(story, IntroChapter, WithInput(strings.NewReader(input)), WithOutput(&out), WithSaveFile(saves))
+		return out.String()
+	}
+
+	out := play("b\n1\nsave left side\nb\nh\n2\nb\nload left side\nh\nq\n")
+	for _, want := range []string{
+		"You are at the start of your journey.",
+		"(steps: 1)",
+		`Game saved to slot "left side".`,
+		"Your path so far:\n  1. Crossroads\n",
+		"======= Right =======",
+		"Your path so far:\n  1. Crossroads\n  2. Left\n",
+		"Exiting the story.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the output to contain %q, but got:\n%s", want, out)
+		}
+	}
+
+	// The save file is replaced without leaving temporary files behind
+	if entries, err := os.ReadDir(filepath.Dir(saves)); err != nil || len(entries) != 1 {
+		t.Errorf("Expected only the save file in its directory, but got %v, %v", entries, err)
+	}
+
+	// The slot is still there in a new game
+	out = play("load\nload left side\n1\nload missing\n")
+	for _, want := range []string{
+		"  left side - Left (",
+		"======= The End =======",
+		`Failed to load the game: no game saved in slot "missing"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the output to contain %q, but got:\n%s", want, out)
+		}
+	}
+}
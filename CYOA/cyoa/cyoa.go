@@ -1,20 +1,30 @@
 // Package cyoa provides the core logic and structures for creating a Choose Your Own Adventure (CYOA) web application.
-// It includes types and functions for loading a story from a JSON file, handling HTTP requests, and rendering story chapters using HTML templates.
+// It includes types and functions for loading a story from a JSON, Twee or Markdown file, handling HTTP requests, and rendering story chapters using HTML templates.
 
 package cyoa
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"path/filepath"
+	"strings"
 )
 
+// IntroChapter is the chapter a story starts with.
+const IntroChapter = "intro"
+
 // Story represents a story, which is a map where the keys are chapter names and the values are the chapters themselves.
 type Story map[string]Chapter
 
 // Option represents an option inside a Chapter, linking to another chapter in the story.
+// An option is only shown when all its conditions hold, and its effects are applied to
+// the player's State when it is chosen, as described on State.
 type Option struct {
-	Text    string `json:"text"`
-	NextArc string `json:"arc"`
+	Text    string   `json:"text"`
+	NextArc string   `json:"arc"`
+	If      []string `json:"if,omitempty"`  // Conditions, e.g. "has key" or "gold >= 5"
+	Set     []string `json:"set,omitempty"` // Effects, e.g. "take key" or "gold -= 5"
 }
 
 // Chapter represents a chapter in a choose your own adventure story.
@@ -22,6 +32,7 @@ type Chapter struct {
 	Title   string   `json:"title"`
 	Story   []string `json:"story"`
 	Options []Option `json:"options"`
+	Set     []string `json:"set,omitempty"` // Effects applied when a player arrives in the chapter
 }
 
 // JSONStory parses a JSON-encoded story from an io.Reader and returns it as a Story.
@@ -34,3 +45,55 @@ func JSONStory(r io.Reader) (Story, error) {
 	}
 	return story, nil
 }
+
+// ParseStory parses a story in the format given by the extension of filename: Twee for
+// ".twee" and ".tw" files, Markdown for ".md" and ".markdown" files, and JSON otherwise.
+func ParseStory(r io.Reader, filename string) (Story, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".twee", ".tw":
+		return TweeStory(r)
+	case ".md", ".markdown":
+		return MarkdownStory(r)
+	default:
+		return JSONStory(r)
+	}
+}
+
+// startAt makes start the IntroChapter of s, renaming it and the options leading to it.
+// It fails if another chapter is already named IntroChapter.
+func startAt(s Story, start string) error {
+	if start == IntroChapter {
+		return nil
+	}
+	if _, ok := s[IntroChapter]; ok {
+		return fmt.Errorf("the story starts at %q but also has a chapter named %q", start, IntroChapter)
+	}
+	ch, ok := s[start]
+	if !ok {
+		return fmt.Errorf("the start chapter %q doesn't exist", start)
+	}
+	delete(s, start)
+	s[IntroChapter] = ch
+	for _, ch := range s {
+		for i := range ch.Options {
+			if ch.Options[i].NextArc == start {
+				ch.Options[i].NextArc = IntroChapter
+			}
+		}
+	}
+	return nil
+}
+
+// paragraphs joins lines of text into paragraphs, which are separated by blank lines.
+func paragraphs(lines []string) []string {
+	var paras, para []string
+	for _, l := range append(lines, "") {
+		if l = strings.TrimSpace(l); l != "" {
+			para = append(para, l)
+		} else if len(para) > 0 {
+			paras = append(paras, strings.Join(para, " "))
+			para = nil
+		}
+	}
+	return paras
+}
@@ -0,0 +1,167 @@
+package cyoa
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLabel is the longest chapter or option label drawn in a graph, in characters.
+// Longer labels are cut short so that large stories stay readable.
+const maxLabel = 40
+
+// graphNode is a chapter, or a missing chapter an option leads to, in a story graph.
+type graphNode struct {
+	id      string // Identifier safe to use in any graph language
+	name    string
+	label   string
+	entry   bool
+	ending  bool
+	missing bool
+}
+
+// graphEdge is an option leading from one chapter to another.
+type graphEdge struct {
+	from, to string // Node identifiers
+	label    string
+}
+
+// storyGraph returns the nodes and edges of the graph of s, the entry chapter first
+// and the other chapters in alphabetical order.
+func storyGraph(s Story, entry string) ([]graphNode, []graphEdge) {
+	names := chapterNames(s)
+	if _, ok := s[entry]; ok {
+		for i, name := range names {
+			if name == entry {
+				names = append(append([]string{entry}, names[:i]...), names[i+1:]...)
+				break
+			}
+		}
+	}
+
+	var nodes []graphNode
+	ids := make(map[string]string)
+	addNode := func(n graphNode) {
+		n.id = fmt.Sprintf("n%d", len(nodes))
+		ids[n.name] = n.id
+		nodes = append(nodes, n)
+	}
+	for _, name := range names {
+		ch := s[name]
+		label := ch.Title
+		if strings.TrimSpace(label) == "" {
+			label = name
+		}
+		addNode(graphNode{name: name, label: shorten(label), entry: name == entry, ending: len(ch.Options) == 0})
+	}
+
+	var edges []graphEdge
+	for _, name := range names {
+		for _, opt := range s[name].Options {
+			if _, ok := ids[opt.NextArc]; !ok {
+				addNode(graphNode{name: opt.NextArc, label: shorten(opt.NextArc), missing: true})
+			}
+			edges = append(edges, graphEdge{from: ids[name], to: ids[opt.NextArc], label: shorten(opt.Text)})
+		}
+	}
+	return nodes, edges
+}
+
+// shorten collapses the whitespace of s and cuts it to maxLabel characters.
+func shorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxLabel {
+		return strings.TrimSpace(string(r[:maxLabel-1])) + "…"
+	}
+	return s
+}
+
+// dotEscaper escapes text for a quoted Graphviz DOT string.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// WriteDOT writes the chapter graph of s as a Graphviz DOT digraph. Chapters are nodes
+// labeled with their title and options are edges labeled with their text. The entry
+// chapter is drawn bold and blue, endings green, and missing chapters dashed red.
+//
+// Example usage:
+//
+//	cyoa.WriteDOT(f, story, cyoa.IntroChapter)
+//	// dot -Tsvg story.dot -o story.svg
+func WriteDOT(w io.Writer, s Story, entry string) error {
+	nodes, edges := storyGraph(s, entry)
+
+	var b strings.Builder
+	b.WriteString("digraph story {\n")
+	b.WriteString("\tnode [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	b.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, n := range nodes {
+		attrs := ""
+		switch {
+		case n.entry:
+			attrs = `, style="rounded,filled,bold", fillcolor="lightblue", penwidth=2`
+		case n.missing:
+			attrs = `, style="rounded,dashed", color="red", fontcolor="red"`
+		case n.ending:
+			attrs = `, style="rounded,filled", fillcolor="palegreen"`
+		}
+		fmt.Fprintf(&b, "\t%s [label=\"%s\"%s];\n", n.id, dotEscaper.Replace(n.label), attrs)
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=\"%s\"];\n", e.from, e.to, dotEscaper.Replace(e.label))
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write the DOT graph: %v", err)
+	}
+	return nil
+}
+
+// mermaidEscaper escapes text for a quoted Mermaid label.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;")
+
+// WriteMermaid writes the chapter graph of s as a Mermaid flowchart, with the same
+// nodes, edges and highlights as WriteDOT. Endings are drawn as rounded stadiums.
+func WriteMermaid(w io.Writer, s Story, entry string) error {
+	nodes, edges := storyGraph(s, entry)
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	var entries, endings, missing []string
+	for _, n := range nodes {
+		label := mermaidEscaper.Replace(n.label)
+		if n.ending {
+			fmt.Fprintf(&b, "    %s([\"%s\"])\n", n.id, label)
+		} else {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", n.id, label)
+		}
+		switch {
+		case n.entry:
+			entries = append(entries, n.id)
+		case n.missing:
+			missing = append(missing, n.id)
+		case n.ending:
+			endings = append(endings, n.id)
+		}
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", e.from, mermaidEscaper.Replace(e.label), e.to)
+	}
+
+	b.WriteString("    classDef entry fill:#add8e6,stroke:#333,stroke-width:3px\n")
+	b.WriteString("    classDef ending fill:#98fb98\n")
+	b.WriteString("    classDef missing stroke:#f00,stroke-dasharray:5 5,color:#f00\n")
+	for _, class := range []struct {
+		name string
+		ids  []string
+	}{{"entry", entries}, {"ending", endings}, {"missing", missing}} {
+		if len(class.ids) > 0 {
+			fmt.Fprintf(&b, "    class %s %s\n", strings.Join(class.ids, ","), class.name)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write the Mermaid graph: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cyoa
+
+import (
+	"strings"
+	"testing"
+)
+
+// graphStory is a small story with an ending, a quoted label and a missing chapter.
+var graphStory = Story{
+	"intro": {Title: "The \"Start\"", Options: []Option{
+		{Text: "Go left", NextArc: "left"},
+		{Text: "Go right", NextArc: "right"},
+	}},
+	"left":  {Title: "Left", Options: []Option{{Text: "Open the door", NextArc: "door"}}},
+	"right": {Title: "The End"},
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b strings.Builder
+	if err := WriteDOT(&b, graphStory, IntroChapter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"digraph story {\n",
+		`n0 [label="The \"Start\"", style="rounded,filled,bold"`,
+		`n1 [label="Left"];`,
+		`n2 [label="The End", style="rounded,filled", fillcolor="palegreen"];`,
+		`n3 [label="door", style="rounded,dashed"`,
+		`n0 -> n1 [label="Go left"];`,
+		`n0 -> n2 [label="Go right"];`,
+		`n1 -> n3 [label="Open the door"];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the graph to contain %q, but got:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMermaid(&b, graphStory, IntroChapter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"flowchart TD\n",
+		`n0["The #quot;Start#quot;"]`,
+		`n2(["The End"])`,
+		`n0 -->|"Go left"| n1`,
+		`n1 -->|"Open the door"| n3`,
+		"class n0 entry\n",
+		"class n2 ending\n",
+		"class n3 missing\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the graph to contain %q, but got:\n%s", want, got)
+		}
+	}
+}
+
+func TestShorten(t *testing.T) {
+	long := strings.Repeat("word ", 20)
+	got := shorten(long)
+	if n := len([]rune(got)); n > maxLabel || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected a label of at most %d characters ending with …, but got %q", maxLabel, got)
+	}
+	if got := shorten("  Go\n left "); got != "Go left" {
+		t.Errorf("Expected %q, but got %q", "Go left", got)
+	}
+}
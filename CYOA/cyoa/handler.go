@@ -1,18 +1,36 @@
 package cyoa
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"html/template"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
+// stateCookie is the name of the cookie that keeps each player's State.
+const stateCookie = "cyoa_state"
+
 // handler is the main HTTP handler for the CYOA story.
 // It implements the http.Handler interface and is responsible for rendering story chapters.
 type handler struct {
 	story  Story
 	tmpl   *template.Template
 	pathFn func(r *http.Request) string
+	key    []byte // Key signing the state cookies, so that players can't edit them
+}
+
+// Page is the data a chapter is rendered with.
+type Page struct {
+	Chapter
+	Name    string   // Name of the chapter, sent back with the chosen option
+	Choices []Choice // Options available to the player, none outside the player's chapter
+	State   State
 }
 
 // HandlerOption represents a functional option for configuring a Handler.
@@ -28,13 +46,19 @@ type HandlerOption func(h *handler)
 func NewHandler(s Story, opts ...HandlerOption) http.Handler {
 	h := &handler{
 		story:  s,
-		tmpl:   MustLoadTemplates("templates"), // Use defualt templates directory
-		pathFn: DefaultPathFn,                  // Default path function to extract chapter names
+		pathFn: DefaultPathFn, // Default path function to extract chapter names
+		key:    make([]byte, 32),
+	}
+	if _, err := rand.Read(h.key); err != nil {
+		log.Fatalf("Error generating the state cookie key: %v", err)
 	}
 	// Apply each option to configure the handler
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.tmpl == nil {
+		h.tmpl = MustLoadTemplates("templates") // Use defualt templates directory
+	}
 
 	return h
 }
@@ -54,22 +78,112 @@ func WithPathFunc(fn func(r *http.Request) string) HandlerOption {
 }
 
 // ServeHTTP handles HTTP requests and renders the appropriate story chapter using the template.
-// If the requested chapter is found in the story, it renders the chapter. Otherwise, it returns a 404 error.
-
+// If the requested chapter is found in the story, it renders the chapter with the options
+// the player's state allows. Otherwise, it returns a 404 error.
+//
+// The state and the chapter the player is in are kept in a signed cookie. A link to
+// "<chapter>?from=<chapter>&option=<index>" chooses an option of the chapter the player
+// is in: its effects are applied and the player is redirected to the next chapter, so
+// that reloading the page doesn't apply them again. Options of other chapters, such as
+// a page reached with the browser's Back button, are refused, and those chapters are
+// shown without options. Adding "?restart" starts over.
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path := h.pathFn(r) // Extract the chapter name from the URL path
 
-	if chapter, ok := h.story[path]; ok {
-		// Render the template with the chapter data
-		err := h.tmpl.ExecuteTemplate(w, "story.html", chapter)
-		if err != nil {
-			log.Printf("Template execution error: %v", err)
-			http.Error(w, "Something went wrong...", http.StatusInternalServerError)
+	chapter, ok := h.story[path]
+	if !ok {
+		// If the chapter is not found, return a 404 error
+		http.Error(w, "Chapter not found.", http.StatusNotFound)
+		return
+	}
+
+	// Start new players, and those starting over, in the chapter they are in
+	q := r.URL.Query()
+	from := q.Get("from")
+	cur, ok := h.readState(r)
+	if !ok || q.Has("restart") {
+		cur = step{Chapter: path}
+		if from != "" {
+			cur.Chapter = from
 		}
+		h.story[cur.Chapter].Enter(&cur.State)
+	}
+
+	// Apply the chosen option, only once and only in the chapter the player is in
+	if from != "" {
+		i, err := strconv.Atoi(q.Get("option"))
+		next := ""
+		if err == nil && from == cur.Chapter {
+			next, err = h.story.Choose(&cur.State, from, i)
+		}
+		if err != nil || from != cur.Chapter || next != path {
+			http.Error(w, "That option isn't available.", http.StatusBadRequest)
+			return
+		}
+		cur.Chapter = next
+	}
+	if err := h.writeState(w, cur); err != nil {
+		log.Printf("State cookie error: %v", err)
+		http.Error(w, "Something went wrong...", http.StatusInternalServerError)
 		return
 	}
-	// If the chapter is not found, return a 404 error
-	http.Error(w, "Chapter not found.", http.StatusNotFound)
+	if len(q) > 0 {
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+		return
+	}
+
+	// Render the template with the chapter data
+	page := Page{Chapter: chapter, Name: path, State: cur.State}
+	if path == cur.Chapter {
+		page.Choices = chapter.Choices(cur.State)
+	}
+	if err := h.tmpl.ExecuteTemplate(w, "story.html", page); err != nil {
+		log.Printf("Template execution error: %v", err)
+		http.Error(w, "Something went wrong...", http.StatusInternalServerError)
+	}
+}
+
+// readState returns the chapter and state in the request's cookie, and whether there is
+// a valid one.
+func (h *handler) readState(r *http.Request) (step, bool) {
+	var st step
+	c, err := r.Cookie(stateCookie)
+	if err != nil {
+		return st, false
+	}
+	data, sig, ok := strings.Cut(c.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(h.sign(data))) {
+		return st, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil || json.Unmarshal(b, &st) != nil {
+		return st, false
+	}
+	return st, true
+}
+
+// writeState sets the signed cookie that keeps the player's chapter and state.
+func (h *handler) writeState(w http.ResponseWriter, st step) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	data := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    data + "." + h.sign(data),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// sign returns the signature of a state cookie value.
+func (h *handler) sign(data string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
 // DefaultPathFn is the default function to extract the chapter name from the URL path.
@@ -77,7 +191,7 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func DefaultPathFn(r *http.Request) string {
 	path := strings.TrimSpace(r.URL.Path)
 	if path == "" || path == "/" {
-		path = "/intro"
+		path = "/" + IntroChapter
 	}
 	return path[1:] // Remove leading '/'
 }
@@ -0,0 +1,103 @@
+package cyoa
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// playerClient returns a function that requests url from h as a single player, keeping
+// the state cookie between requests.
+func playerClient(h http.Handler) func(url string) *httptest.ResponseRecorder {
+	var cookies []*http.Cookie
+	return func(url string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if c := w.Result().Cookies(); len(c) > 0 {
+			cookies = c
+		}
+		return w
+	}
+}
+
+func TestHandlerState(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Intro", Options: []Option{
+			{Text: "Take the key", NextArc: "intro", If: []string{"not has key"}, Set: []string{"take key"}},
+			{Text: "Open the door", NextArc: "door", If: []string{"has key"}},
+		}},
+		"door": {Title: "Door"},
+	}
+	tpl := template.Must(template.New("story.html").Parse(
+		`{{.State}}|{{range .Choices}}<a href="/{{.NextArc}}?from={{$.Name}}&option={{.Index}}">{{.Text}}</a>{{end}}`))
+	h := NewHandler(story, WithTemplate(tpl))
+	get := playerClient(h)
+
+	if body := get("/").Body.String(); !strings.Contains(body, "Take the key") || strings.Contains(body, "Open the door") {
+		t.Fatalf("Expected only the key to be available, but got %q", body)
+	}
+	if w := get("/intro?from=intro&option=0"); w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect after choosing an option, but got %d", w.Code)
+	}
+	if body := get("/intro").Body.String(); !strings.HasPrefix(body, "items: key|") || !strings.Contains(body, "Open the door") {
+		t.Errorf("Expected the key to be taken and the door available, but got %q", body)
+	}
+	if w := get("/intro?from=intro&option=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unavailable option to be refused, but got %d", w.Code)
+	}
+
+	// A tampered cookie starts a new game
+	w := get("/intro")
+	c := w.Result().Cookies()[0]
+	c.Value = "e30" + c.Value[3:]
+	r := httptest.NewRequest(http.MethodGet, "/intro", nil)
+	r.AddCookie(c)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if body := w.Body.String(); !strings.HasPrefix(body, "|") {
+		t.Errorf("Expected a tampered cookie to be ignored, but got %q", body)
+	}
+}
+
+func TestHandlerReplay(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Intro", Options: []Option{{Text: "Work", NextArc: "shop", Set: []string{"gold += 5"}}}},
+		"shop": {Title: "Shop", Options: []Option{
+			{Text: "Buy the ship", NextArc: "end", If: []string{"gold >= 15"}},
+			{Text: "Go back", NextArc: "intro"},
+		}},
+		"end": {Title: "The End"},
+	}
+	tpl := template.Must(template.New("story.html").Parse(`{{.State}}|{{range .Choices}}{{.Text}};{{end}}`))
+	get := playerClient(NewHandler(story, WithTemplate(tpl)))
+
+	get("/")
+	if w := get("/shop?from=intro&option=0"); w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect after choosing an option, but got %d", w.Code)
+	}
+	// Replaying the option, e.g. after pressing Back, is refused
+	for i := 0; i < 2; i++ {
+		if w := get("/shop?from=intro&option=0"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected a replayed option to be refused, but got %d", w.Code)
+		}
+	}
+	if body := get("/intro").Body.String(); body != "gold: 5|" {
+		t.Errorf("Expected the previous chapter without options, but got %q", body)
+	}
+	if body := get("/shop").Body.String(); body != "gold: 5|Go back;" {
+		t.Errorf("Expected gold: 5 and no ship for sale, but got %q", body)
+	}
+
+	// Going back through the story's own options works
+	get("/intro?from=shop&option=1")
+	get("/shop?from=intro&option=0")
+	if body := get("/shop").Body.String(); body != "gold: 10|Go back;" {
+		t.Errorf("Expected gold: 10 after working twice, but got %q", body)
+	}
+}
@@ -0,0 +1,110 @@
+package cyoa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// headingRe matches a Markdown heading, with optional closing hashes.
+	headingRe = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	// headingIDRe matches an explicit chapter name at the end of a heading, as in "{#intro}".
+	headingIDRe = regexp.MustCompile(`\s*\{#([^}\s]+)\}$`)
+	// optionRe matches a list item that is only a link to a chapter, as in "- [Go left](#left)".
+	optionRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([^\]]+)\]\(#([^)\s]+)\)\s*$`)
+)
+
+// MarkdownStory parses a story written in Markdown. Each heading starts a chapter titled
+// after it, and list items that are only a link to a heading are the options:
+//
+//	# The Beginning
+//	It was a dark and stormy night...
+//
+//	- [Walk towards the light](#the-light)
+//	- [Stay where you are](#darkness)
+//
+//	## The Light {#the-light}
+//
+// A chapter is named after its heading like a Markdown anchor, lowercased with spaces
+// turned into hyphens and punctuation removed, or by an explicit "{#name}" at the end of
+// the heading. Paragraphs are separated by blank lines, and text before the first heading
+// is ignored. The first chapter becomes the IntroChapter unless a chapter has that name.
+func MarkdownStory(r io.Reader) (Story, error) {
+	story := make(Story)
+	first, name := "", ""
+	var ch Chapter
+	var prose []string
+	// Add the chapter read so far to the story
+	flush := func() {
+		if name != "" {
+			ch.Story = paragraphs(prose)
+			story[name] = ch
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		l := scanner.Text()
+		if m := headingRe.FindStringSubmatch(l); m != nil {
+			flush()
+			title := m[1]
+			name = anchor(title)
+			if id := headingIDRe.FindStringSubmatch(title); id != nil {
+				title, name = strings.TrimSpace(title[:len(title)-len(id[0])]), id[1]
+			}
+			if name == "" {
+				return nil, fmt.Errorf("line %d: heading without a chapter name", line)
+			}
+			if _, ok := story[name]; ok {
+				return nil, fmt.Errorf("line %d: chapter %q is defined twice; give one a name with {#name}", line, name)
+			}
+			if first == "" {
+				first = name
+			}
+			ch, prose = Chapter{Title: title}, nil
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		if m := optionRe.FindStringSubmatch(l); m != nil {
+			ch.Options = append(ch.Options, Option{Text: strings.TrimSpace(m[1]), NextArc: m[2]})
+			continue
+		}
+		prose = append(prose, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if first == "" {
+		return nil, fmt.Errorf("no chapters found; start each chapter with a heading")
+	}
+	if _, ok := story[IntroChapter]; !ok {
+		if err := startAt(story, first); err != nil {
+			return nil, err
+		}
+	}
+	return story, nil
+}
+
+// anchor returns the Markdown anchor of a heading: its letters, digits, hyphens and
+// underscores, lowercased, with spaces turned into hyphens.
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package cyoa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownStory(t *testing.T) {
+	src := `Notes for writers, not part of the story.
+
+# The Beginning
+It was a dark and stormy night...
+You see a light.
+
+- [Walk towards the light](#the-light)
+* [Stay where you are](#darkness)
+- A list item that is not an option
+
+## The Light! ##
+The light grows brighter.
+
+1. [Start over](#the-beginning)
+
+## Darkness {#darkness}
+Nothing happens.
+`
+	story, err := MarkdownStory(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Story{
+		"intro": {Title: "The Beginning", Story: []string{
+			"It was a dark and stormy night... You see a light.",
+			"- A list item that is not an option",
+		}, Options: []Option{
+			{Text: "Walk towards the light", NextArc: "the-light"},
+			{Text: "Stay where you are", NextArc: "darkness"},
+		}},
+		"the-light": {Title: "The Light!", Story: []string{"The light grows brighter."}, Options: []Option{{Text: "Start over", NextArc: "intro"}}},
+		"darkness":  {Title: "Darkness", Story: []string{"Nothing happens."}},
+	}
+	if !reflect.DeepEqual(story, want) {
+		t.Errorf("Expected %+v, but got %+v", want, story)
+	}
+}
+
+func TestMarkdownStoryErrors(t *testing.T) {
+	for _, src := range []string{
+		"no headings at all\n",
+		"# Cave\n\n# Cave\n",
+		"# {#}\n",
+	} {
+		if _, err := MarkdownStory(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected an error for %q", src)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package cyoa
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// State is what a player has gathered on the way through a story: numeric variables,
+// such as gold, and items, such as a key. Variables that were never set are 0.
+//
+// Option conditions test the state. Each is one of "has <item>", "<var> <op> <number>"
+// with op one of == != < <= > >=, or "<var>" for a variable that is not 0, and may be
+// negated with a leading "not". Option and chapter effects change the state. Each is one
+// of "take <item>", "drop <item>", "<var> = <number>", "<var> += <number>" or
+// "<var> -= <number>".
+type State struct {
+	Vars  map[string]int `json:"vars,omitempty"`
+	Items []string       `json:"items,omitempty"` // Sorted, without duplicates
+}
+
+// Choice is an option a player may choose in a chapter, with its position among the
+// options of the chapter.
+type Choice struct {
+	Option
+	Index int
+}
+
+var (
+	// comparisonRe matches a variable condition such as "gold >= 5".
+	comparisonRe = regexp.MustCompile(`^([A-Za-z_]\w*)\s*(==|!=|<=|>=|<|>|=)\s*(-?\d+)$`)
+	// assignmentRe matches a variable effect such as "gold += 5".
+	assignmentRe = regexp.MustCompile(`^([A-Za-z_]\w*)\s*(\+=|-=|=)\s*(-?\d+)$`)
+	// nameRe matches a variable name on its own.
+	nameRe = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+)
+
+// condition is a parsed condition of an option.
+type condition struct {
+	negate bool
+	item   string // Item the player must have, empty for a variable condition
+	name   string
+	op     string
+	value  int
+}
+
+// parseCondition parses a condition of an option, as described on State.
+func parseCondition(s string) (condition, error) {
+	var c condition
+	s = strings.TrimSpace(s)
+	if rest, ok := cutWord(s, "not"); ok {
+		c.negate, s = true, rest
+	}
+	if item, ok := cutWord(s, "has"); ok {
+		if item == "" {
+			return c, fmt.Errorf("invalid condition %q: missing item", s)
+		}
+		c.item = item
+		return c, nil
+	}
+	if nameRe.MatchString(s) {
+		c.name, c.op = s, "!="
+		return c, nil
+	}
+	m := comparisonRe.FindStringSubmatch(s)
+	if m == nil {
+		return c, fmt.Errorf("invalid condition %q", s)
+	}
+	c.name, c.op = m[1], m[2]
+	c.value, _ = strconv.Atoi(m[3])
+	return c, nil
+}
+
+// holds reports whether the condition holds in st.
+func (c condition) holds(st State) bool {
+	var ok bool
+	if c.item != "" {
+		ok = st.Has(c.item)
+	} else {
+		v := st.Vars[c.name]
+		switch c.op {
+		case "==", "=":
+			ok = v == c.value
+		case "!=":
+			ok = v != c.value
+		case "<":
+			ok = v < c.value
+		case "<=":
+			ok = v <= c.value
+		case ">":
+			ok = v > c.value
+		case ">=":
+			ok = v >= c.value
+		}
+	}
+	return ok != c.negate
+}
+
+// effect is a parsed effect of an option or chapter.
+type effect struct {
+	take, drop string // Item to add to or remove from the inventory
+	name       string
+	op         string
+	value      int
+}
+
+// parseEffect parses an effect of an option or chapter, as described on State.
+func parseEffect(s string) (effect, error) {
+	var e effect
+	s = strings.TrimSpace(s)
+	if item, ok := cutWord(s, "take"); ok && item != "" {
+		e.take = item
+		return e, nil
+	}
+	if item, ok := cutWord(s, "drop"); ok && item != "" {
+		e.drop = item
+		return e, nil
+	}
+	m := assignmentRe.FindStringSubmatch(s)
+	if m == nil {
+		return e, fmt.Errorf("invalid effect %q", s)
+	}
+	e.name, e.op = m[1], m[2]
+	e.value, _ = strconv.Atoi(m[3])
+	return e, nil
+}
+
+// apply applies the effect to st.
+func (e effect) apply(st *State) {
+	switch {
+	case e.take != "":
+		st.Take(e.take)
+	case e.drop != "":
+		st.Drop(e.drop)
+	default:
+		if st.Vars == nil {
+			st.Vars = make(map[string]int)
+		}
+		switch e.op {
+		case "=":
+			st.Vars[e.name] = e.value
+		case "+=":
+			st.Vars[e.name] += e.value
+		case "-=":
+			st.Vars[e.name] -= e.value
+		}
+	}
+}
+
+// cutWord returns what follows the keyword word at the start of s, and whether s starts
+// with it.
+func cutWord(s, word string) (string, bool) {
+	if s == word {
+		return "", true
+	}
+	if rest, ok := strings.CutPrefix(s, word+" "); ok {
+		return strings.TrimSpace(rest), true
+	}
+	return "", false
+}
+
+// Has reports whether the player has the item.
+func (st State) Has(item string) bool {
+	i := sort.SearchStrings(st.Items, item)
+	return i < len(st.Items) && st.Items[i] == item
+}
+
+// Take adds the item to the inventory.
+func (st *State) Take(item string) {
+	if i := sort.SearchStrings(st.Items, item); i == len(st.Items) || st.Items[i] != item {
+		st.Items = append(st.Items[:i], append([]string{item}, st.Items[i:]...)...)
+	}
+}
+
+// Drop removes the item from the inventory.
+func (st *State) Drop(item string) {
+	if i := sort.SearchStrings(st.Items, item); i < len(st.Items) && st.Items[i] == item {
+		st.Items = append(st.Items[:i], st.Items[i+1:]...)
+	}
+}
+
+// clone returns a copy of the state that shares nothing with it.
+func (st State) clone() State {
+	c := State{Items: append([]string(nil), st.Items...)}
+	if st.Vars != nil {
+		c.Vars = make(map[string]int, len(st.Vars))
+		for name, v := range st.Vars {
+			c.Vars[name] = v
+		}
+	}
+	return c
+}
+
+// String describes the state as "items: key, map; gold: 5", or returns an empty string
+// when the player has nothing.
+func (st State) String() string {
+	var parts []string
+	if len(st.Items) > 0 {
+		parts = append(parts, "items: "+strings.Join(st.Items, ", "))
+	}
+	names := make([]string, 0, len(st.Vars))
+	for name := range st.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, st.Vars[name]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Available reports whether every condition of the option holds in st. Options with
+// invalid conditions are never available; Validate reports them.
+func (o Option) Available(st State) bool {
+	for _, s := range o.If {
+		c, err := parseCondition(s)
+		if err != nil || !c.holds(st) {
+			return false
+		}
+	}
+	return true
+}
+
+// Choices returns the options of the chapter that are available in st.
+func (c Chapter) Choices(st State) []Choice {
+	var choices []Choice
+	for i, opt := range c.Options {
+		if opt.Available(st) {
+			choices = append(choices, Choice{Option: opt, Index: i})
+		}
+	}
+	return choices
+}
+
+// Enter applies the effects of the chapter to st, when a player arrives in it.
+func (c Chapter) Enter(st *State) {
+	applyEffects(st, c.Set)
+}
+
+// Choose applies the option at index i of the chapter to st: the effects of the option,
+// then those of the chapter it leads to. It returns the name of that chapter, or an
+// error if the option doesn't exist or isn't available in st.
+func (s Story) Choose(st *State, chapter string, i int) (string, error) {
+	ch, ok := s[chapter]
+	if !ok {
+		return "", fmt.Errorf("chapter %q not found", chapter)
+	}
+	if i < 0 || i >= len(ch.Options) {
+		return "", fmt.Errorf("chapter %q has no option %d", chapter, i+1)
+	}
+	opt := ch.Options[i]
+	if !opt.Available(*st) {
+		return "", fmt.Errorf("option %d of chapter %q isn't available", i+1, chapter)
+	}
+	applyEffects(st, opt.Set)
+	s[opt.NextArc].Enter(st)
+	return opt.NextArc, nil
+}
+
+// applyEffects applies the effects to st, skipping invalid ones, which Validate reports.
+func applyEffects(st *State, effects []string) {
+	for _, s := range effects {
+		if e, err := parseEffect(s); err == nil {
+			e.apply(st)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cyoa
+
+import "testing"
+
+func TestConditions(t *testing.T) {
+	st := State{Vars: map[string]int{"gold": 5}, Items: []string{"key"}}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"has key", true},
+		{"has map", false},
+		{"not has map", true},
+		{"gold >= 5", true},
+		{"gold>5", false},
+		{"gold == 5", true},
+		{"gold != 5", false},
+		{"gold", true},
+		{"not silver", true},
+		{"silver < 0", false},
+	}
+	for _, tt := range tests {
+		c, err := parseCondition(tt.cond)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tt.cond, err)
+			continue
+		}
+		if got := c.holds(st); got != tt.want {
+			t.Errorf("Expected %q to be %v, but got %v", tt.cond, tt.want, got)
+		}
+	}
+
+	for _, cond := range []string{"", "has", "gold >= lots", "gold => 5", "1 < gold"} {
+		if _, err := parseCondition(cond); err == nil {
+			t.Errorf("Expected an error for the condition %q", cond)
+		}
+	}
+}
+
+func TestEffects(t *testing.T) {
+	var st State
+	applyEffects(&st, []string{"take key", "take map", "take key", "gold = 3", "gold += 4", "gold -= 2", "drop map"})
+	if got, want := st.String(), "items: key; gold: 5"; got != want {
+		t.Errorf("Expected the state %q, but got %q", want, got)
+	}
+
+	for _, e := range []string{"", "take", "gold", "gold *= 2", "gold += x"} {
+		if _, err := parseEffect(e); err == nil {
+			t.Errorf("Expected an error for the effect %q", e)
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Intro", Set: []string{"visits += 1"}, Options: []Option{
+			{Text: "Open the chest", NextArc: "chest", If: []string{"not has key"}},
+			{Text: "Buy the ship", NextArc: "end", If: []string{"gold >= 5"}},
+		}},
+		"chest": {Title: "Chest", Set: []string{"gold += 5"}, Options: []Option{
+			{Text: "Go back", NextArc: "intro", Set: []string{"take key"}},
+		}},
+		"end": {Title: "The End"},
+	}
+
+	var st State
+	story[IntroChapter].Enter(&st)
+	if choices := story[IntroChapter].Choices(st); len(choices) != 1 || choices[0].Index != 0 {
+		t.Fatalf("Expected only the chest to be available, but got %v", choices)
+	}
+	if _, err := story.Choose(&st, IntroChapter, 1); err == nil {
+		t.Error("Expected an error when choosing an unavailable option")
+	}
+
+	for _, step := range []struct {
+		chapter string
+		option  int
+		next    string
+	}{{"intro", 0, "chest"}, {"chest", 0, "intro"}, {"intro", 1, "end"}} {
+		next, err := story.Choose(&st, step.chapter, step.option)
+		if err != nil || next != step.next {
+			t.Fatalf("Expected option %d of %q to lead to %q, but got %q, %v", step.option, step.chapter, step.next, next, err)
+		}
+	}
+	if got, want := st.String(), "items: key; gold: 5; visits: 2"; got != want {
+		t.Errorf("Expected the state %q, but got %q", want, got)
+	}
+}
@@ -0,0 +1,176 @@
+package cyoa
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// tweeLinkRe matches a Twine link such as [[Go left->Left]].
+var tweeLinkRe = regexp.MustCompile(`\[\[(.*?)\]\]`)
+
+// TweeStory parses a story in the Twee 3 text format used by Twine. Each passage is a
+// chapter named and titled after the passage, and its links are the options:
+//
+//	:: Start
+//	You stand at a crossroads.
+//	[[Go left->Left]]
+//	[[Right<-Go right]]
+//	[[Go back|Start]]
+//	[[Left]]
+//
+// Lines made only of links are left out of the chapter text, and links within a line
+// are replaced by their text. Paragraphs are separated by blank lines. The start passage
+// given by StoryData, or the passage named "Start", becomes the IntroChapter. The
+// StoryTitle and StoryData passages and passages tagged script or stylesheet are skipped.
+func TweeStory(r io.Reader) (Story, error) {
+	story := make(Story)
+	start := ""
+
+	var (
+		name, tags string
+		text       []string
+		inPassage  bool
+	)
+	// Add the passage read so far to the story
+	flush := func() error {
+		if !inPassage {
+			return nil
+		}
+		switch {
+		case name == "StoryTitle":
+		case name == "StoryData":
+			var data struct {
+				Start string `json:"start"`
+			}
+			if err := json.Unmarshal([]byte(strings.Join(text, "\n")), &data); err != nil {
+				return fmt.Errorf("invalid StoryData: %v", err)
+			}
+			start = data.Start
+		case hasTag(tags, "script"), hasTag(tags, "stylesheet"):
+		default:
+			if _, ok := story[name]; ok {
+				return fmt.Errorf("passage %q is defined twice", name)
+			}
+			story[name] = tweeChapter(name, text)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		l := scanner.Text()
+		if !strings.HasPrefix(l, "::") {
+			text = append(text, l)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		name, tags = parseTweeHeader(l[2:])
+		if name == "" {
+			return nil, fmt.Errorf("line %d: passage without a name", line)
+		}
+		text, inPassage = nil, true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// Start at the start passage unless the story already has an intro
+	if start == "" {
+		if _, ok := story[IntroChapter]; ok {
+			return story, nil
+		}
+		start = "Start"
+	}
+	if err := startAt(story, start); err != nil {
+		return nil, err
+	}
+	return story, nil
+}
+
+// parseTweeHeader returns the name and tags of the passage header h, what follows "::".
+// The passage metadata is ignored.
+func parseTweeHeader(h string) (name, tags string) {
+	var b strings.Builder
+	for i := 0; i < len(h); i++ {
+		switch c := h[i]; c {
+		case '\\':
+			if i+1 < len(h) {
+				i++
+				b.WriteByte(h[i])
+			}
+		case '[':
+			if end := strings.IndexByte(h[i:], ']'); end >= 0 {
+				tags = h[i+1 : i+end]
+			}
+			return strings.TrimSpace(b.String()), tags
+		case '{':
+			return strings.TrimSpace(b.String()), tags
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String()), tags
+}
+
+// hasTag reports whether tag is in the space separated list of passage tags.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Fields(tags) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// tweeChapter builds the chapter of the passage name from its lines of text.
+func tweeChapter(name string, lines []string) Chapter {
+	ch := Chapter{Title: name}
+	var prose []string
+	for _, l := range lines {
+		links := tweeLinkRe.FindAllStringSubmatch(l, -1)
+		for _, m := range links {
+			text, target := parseTweeLink(m[1])
+			ch.Options = append(ch.Options, Option{Text: text, NextArc: target})
+		}
+		if len(links) > 0 && strings.TrimSpace(tweeLinkRe.ReplaceAllString(l, "")) == "" {
+			continue
+		}
+		prose = append(prose, tweeLinkRe.ReplaceAllStringFunc(l, func(link string) string {
+			text, _ := parseTweeLink(link[2 : len(link)-2])
+			return text
+		}))
+	}
+	ch.Story = paragraphs(prose)
+	return ch
+}
+
+// parseTweeLink returns the text and target of the inside of a Twine link. Arrows take
+// precedence over a bar, and a link without either is its own text:
+// "text->target", "target<-text", "text|target" or "target".
+func parseTweeLink(link string) (text, target string) {
+	// Drop SugarCube setters, as in [[text|target][$gold to 5]]
+	if i := strings.Index(link, "]["); i >= 0 {
+		link = link[:i]
+	}
+	if i := strings.LastIndex(link, "->"); i >= 0 {
+		return strings.TrimSpace(link[:i]), strings.TrimSpace(link[i+2:])
+	}
+	if i := strings.Index(link, "<-"); i >= 0 {
+		return strings.TrimSpace(link[i+2:]), strings.TrimSpace(link[:i])
+	}
+	if i := strings.Index(link, "|"); i >= 0 {
+		return strings.TrimSpace(link[:i]), strings.TrimSpace(link[i+1:])
+	}
+	link = strings.TrimSpace(link)
+	return link, link
+}
@@ -0,0 +1,72 @@
+package cyoa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTweeStory(t *testing.T) {
+	src := `Ignored text before the first passage.
+
+:: StoryTitle
+The Crossroads
+
+:: StoryData
+{"ifid": "D674C58C-DEFA-4F70-B7A2-27742230C0FC", "start": "Crossroads"}
+
+:: Crossroads [start] {"position":"100,100"}
+You stand at a crossroads.
+The wind is cold.
+
+You could [[wait->Crossroads]] a little longer.
+[[Go left->Left]]
+[[Right<-Go right]]
+
+:: Left
+A dead end. [[Go back|Crossroads]]
+
+:: Right \[east\]
+[[The End]]
+
+:: The End
+Thanks for playing.
+
+:: Style [stylesheet]
+body { color: red; }
+`
+	story, err := TweeStory(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Story{
+		"intro": {Title: "Crossroads", Story: []string{
+			"You stand at a crossroads. The wind is cold.",
+			"You could wait a little longer.",
+		}, Options: []Option{
+			{Text: "wait", NextArc: "intro"},
+			{Text: "Go left", NextArc: "Left"},
+			{Text: "Go right", NextArc: "Right"},
+		}},
+		"Left":         {Title: "Left", Story: []string{"A dead end. Go back"}, Options: []Option{{Text: "Go back", NextArc: "intro"}}},
+		"Right [east]": {Title: "Right [east]", Options: []Option{{Text: "The End", NextArc: "The End"}}},
+		"The End":      {Title: "The End", Story: []string{"Thanks for playing."}},
+	}
+	if !reflect.DeepEqual(story, want) {
+		t.Errorf("Expected %+v, but got %+v", want, story)
+	}
+}
+
+func TestTweeStoryErrors(t *testing.T) {
+	for _, src := range []string{
+		":: A\n\n:: A\n",
+		":: StoryData\nnot json\n",
+		":: StoryData\n{\"start\": \"Start\"}\n\n:: Start\n\n:: intro\n",
+		":: Begin\n",
+	} {
+		if _, err := TweeStory(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected an error for %q", src)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cyoa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells whether an Issue breaks a story or only makes it worse to play.
+type Severity int
+
+const (
+	Warning Severity = iota // The story works, but part of it can't be played as intended
+	Error                   // Readers can get stuck on a missing chapter or a blank option
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is a problem found in a story by Validate.
+type Issue struct {
+	Chapter  string // Chapter the issue was found in, empty for the whole story
+	Severity Severity
+	Message  string
+}
+
+// String formats the issue as "chapter: severity: message".
+func (i Issue) String() string {
+	if i.Chapter == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Chapter, i.Severity, i.Message)
+}
+
+// HasErrors reports whether any of the issues is an Error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the chapter graph of a story that starts at the entry chapter. It
+// reports a missing entry chapter, options with empty text or leading to chapters that
+// don't exist, conditions or effects that can't be parsed, chapters that can't be
+// reached from the entry, and chapters from which no ending (a chapter without options)
+// can be reached. Conditions are ignored when following options, so a chapter behind
+// an option whose conditions never hold still counts as reachable. Issues are sorted
+// by chapter.
+func Validate(s Story, entry string) []Issue {
+	var issues []Issue
+	add := func(chapter string, severity Severity, format string, args ...interface{}) {
+		issues = append(issues, Issue{Chapter: chapter, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	_, hasEntry := s[entry]
+	if !hasEntry {
+		add("", Error, "the entry chapter %q doesn't exist", entry)
+	}
+	for _, name := range chapterNames(s) {
+		for _, e := range s[name].Set {
+			if _, err := parseEffect(e); err != nil {
+				add(name, Error, "%v", err)
+			}
+		}
+		for i, opt := range s[name].Options {
+			if strings.TrimSpace(opt.Text) == "" {
+				add(name, Error, "option %d has no text", i+1)
+			}
+			if _, ok := s[opt.NextArc]; !ok {
+				add(name, Error, "option %d leads to the missing chapter %q", i+1, opt.NextArc)
+			}
+			for _, c := range opt.If {
+				if _, err := parseCondition(c); err != nil {
+					add(name, Error, "option %d has an %v", i+1, err)
+				}
+			}
+			for _, e := range opt.Set {
+				if _, err := parseEffect(e); err != nil {
+					add(name, Error, "option %d has an %v", i+1, err)
+				}
+			}
+		}
+	}
+
+	reachable := reachableFrom(s, entry)
+	finishes := canFinish(s)
+	for _, name := range chapterNames(s) {
+		if hasEntry && !reachable[name] {
+			add(name, Warning, "the chapter can't be reached from %q", entry)
+		}
+		if !finishes[name] {
+			add(name, Warning, "no ending can be reached from the chapter")
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Chapter < issues[j].Chapter
+	})
+	return issues
+}
+
+// chapterNames returns the names of the chapters of s in alphabetical order.
+func chapterNames(s Story) []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reachableFrom returns the chapters that can be reached from entry, including itself.
+func reachableFrom(s Story, entry string) map[string]bool {
+	seen := make(map[string]bool)
+	if _, ok := s[entry]; !ok {
+		return seen
+	}
+	queue := []string{entry}
+	seen[entry] = true
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, opt := range s[name].Options {
+			if _, ok := s[opt.NextArc]; ok && !seen[opt.NextArc] {
+				seen[opt.NextArc] = true
+				queue = append(queue, opt.NextArc)
+			}
+		}
+	}
+	return seen
+}
+
+// canFinish returns the chapters from which an ending can be reached, by walking the
+// options backwards from every ending.
+func canFinish(s Story) map[string]bool {
+	from := make(map[string][]string) // Chapters with an option to each chapter
+	var queue []string
+	for name, ch := range s {
+		if len(ch.Options) == 0 {
+			queue = append(queue, name)
+		}
+		for _, opt := range ch.Options {
+			from[opt.NextArc] = append(from[opt.NextArc], name)
+		}
+	}
+
+	finishes := make(map[string]bool)
+	for _, name := range queue {
+		finishes[name] = true
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, prev := range from[name] {
+			if !finishes[prev] {
+				finishes[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+	return finishes
+}
@@ -0,0 +1,60 @@
+package cyoa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Intro", Options: []Option{
+			{Text: "Go left", NextArc: "left"},
+			{Text: "Go right", NextArc: "right"},
+			{Text: " ", NextArc: "end"},
+		}},
+		"left":   {Title: "Left", Options: []Option{{Text: "Open the door", NextArc: "door"}}},
+		"right":  {Title: "Right", Options: []Option{{Text: "Walk on", NextArc: "loop"}}},
+		"loop":   {Title: "Loop", Options: []Option{{Text: "Walk back", NextArc: "right"}}},
+		"end":    {Title: "The End"},
+		"secret": {Title: "Secret"},
+	}
+
+	want := []string{
+		"intro: error: option 3 has no text",
+		`left: error: option 1 leads to the missing chapter "door"`,
+		"left: warning: no ending can be reached from the chapter",
+		"loop: warning: no ending can be reached from the chapter",
+		"right: warning: no ending can be reached from the chapter",
+		`secret: warning: the chapter can't be reached from "intro"`,
+	}
+	issues := Validate(story, IntroChapter)
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, but got %v", len(want), issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != want[i] {
+			t.Errorf("Expected issue %q, but got %q", want[i], issue)
+		}
+	}
+	if !HasErrors(issues) {
+		t.Error("Expected HasErrors to report the errors")
+	}
+}
+
+func TestValidateMissingEntry(t *testing.T) {
+	story := Story{"start": {Title: "Start"}}
+	issues := Validate(story, IntroChapter)
+	if len(issues) != 1 || issues[0].Severity != Error || !strings.Contains(issues[0].Message, `"intro"`) {
+		t.Errorf("Expected a single error for the missing entry chapter, but got %v", issues)
+	}
+}
+
+func TestValidateValidStory(t *testing.T) {
+	story := Story{
+		"intro": {Title: "Intro", Options: []Option{{Text: "Go on", NextArc: "end"}, {Text: "Stay", NextArc: "intro"}}},
+		"end":   {Title: "The End"},
+	}
+	if issues := Validate(story, IntroChapter); len(issues) != 0 {
+		t.Errorf("Expected no issues, but got %v", issues)
+	}
+}
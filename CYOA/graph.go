@@ -0,0 +1,67 @@
+package main
+
+import (
+	"CYOA/cyoa"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// graph implements the "graph" subcommand, which draws the chapters of a story and the
+// options between them as a Graphviz DOT or Mermaid graph.
+func graph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	storyFile := fs.String("file", "story.json", "Path to a JSON, Twee or Markdown story file")
+	entry := fs.String("entry", cyoa.IntroChapter, "The chapter the story starts with")
+	format := fs.String("format", "", "Graph format: dot or mermaid. Defaults to the extension of -o, or dot")
+	output := fs.String("o", "", "Path of the file to write the graph to. Defaults to the standard output")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = graphFormat(*output)
+	}
+	write := cyoa.WriteDOT
+	switch *format {
+	case "dot":
+	case "mermaid":
+		write = cyoa.WriteMermaid
+	default:
+		log.Fatalf("Unsupported graph format %q. Please use dot or mermaid.", *format)
+	}
+
+	f, err := os.Open(*storyFile)
+	if err != nil {
+		log.Fatalf("Error in opening file %v, Error: %v", *storyFile, err)
+	}
+	story, err := cyoa.ParseStory(f, *storyFile)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Error in parsing file %v, Error: %v", *storyFile, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		out, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error in creating file %v, Error: %v", *output, err)
+		}
+		defer out.Close()
+		w = out
+	}
+	if err := write(w, story, *entry); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// graphFormat guesses the graph format from the extension of the output file.
+func graphFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mmd", ".mermaid":
+		return "mermaid"
+	default:
+		return "dot"
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"CYOA/cyoa"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// lint implements the "lint" subcommand, which checks story files and prints every
+// issue with its chapter. It exits with status 1 if any story has errors.
+func lint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	storyFile := fs.String("file", "story.json", "Path to a JSON, Twee or Markdown story file; more files can follow the flags")
+	entry := fs.String("entry", cyoa.IntroChapter, "The chapter the story starts with")
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{*storyFile}
+	}
+
+	errors, warnings := 0, 0
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Fatalf("Error in opening file %v, Error: %v", file, err)
+		}
+		story, err := cyoa.ParseStory(f, file)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Error in parsing file %v, Error: %v", file, err)
+		}
+		for _, issue := range cyoa.Validate(story, *entry) {
+			fmt.Printf("%s: %s\n", file, issue)
+			if issue.Severity == cyoa.Error {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	fmt.Printf("%d error(s), %d warning(s)\n", errors, warnings)
+	if errors > 0 {
+		os.Exit(1)
+	}
+}
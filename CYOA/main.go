@@ -7,16 +7,30 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 // main.go serves as the entry point for the CYOA web application.
-// It reads a JSON story file, initializes the handler with templates, and starts an HTTP server.
+// It reads a JSON, Twee or Markdown story file, initializes the handler with templates, and starts an HTTP server.
 func main() {
+	// Dispatch subcommands before parsing the flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint":
+			lint(os.Args[2:])
+			return
+		case "graph":
+			graph(os.Args[2:])
+			return
+		}
+	}
+
 	// Parse command-line flags
 	port := flag.Int("port", 3000, "the port to start the CYOA web application on")
 	cliFlow := flag.Bool("cli", false, "The status if for cli flow")
-	storyFile := flag.String("file", "", "Path to a JSON file containing the choose your own adventure story. Example: -config=story.json")
+	storyFile := flag.String("file", "", "Path to a JSON, Twee (.twee) or Markdown (.md) file containing the choose your own adventure story. Example: -file=story.json")
+	saveFile := flag.String("saves", "", "Path to the file the CLI keeps saved games in. Defaults to the story file name with a .saves.json extension")
 	flag.Parse()
 
 	if *storyFile == "" {
@@ -29,14 +43,26 @@ func main() {
 		log.Fatalf("Error in opening file %v, Error: %v", *storyFile, err)
 	}
 
-	story, err := cyoa.JSONStory(f)
+	story, err := cyoa.ParseStory(f, *storyFile)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Refuse to serve a story readers can get stuck in
+	issues := cyoa.Validate(story, cyoa.IntroChapter)
+	for _, issue := range issues {
+		log.Printf("%s: %s", *storyFile, issue)
+	}
+	if cyoa.HasErrors(issues) {
+		log.Fatalf("The story in %s has errors. Run \"lint -file=%s\" to check it.", *storyFile, *storyFile)
+	}
+
 	// Check if cli flag exists to initiate cli flow
 	if *cliFlow {
-		cyoa.CLIFlow(story, "intro") // Start the story from the "intro" chapter
+		if *saveFile == "" {
+			*saveFile = strings.TrimSuffix(*storyFile, filepath.Ext(*storyFile)) + ".saves.json"
+		}
+		cyoa.CLIFlow(story, cyoa.IntroChapter, cyoa.WithSaveFile(*saveFile)) // Start the story from the "intro" chapter
 		return
 	}
 
@@ -54,11 +80,12 @@ func main() {
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), mux))
 }
 
-// customPathFn is a custom function that defaults to "start" instead of "intro".
+// customPathFn extracts the chapter name from paths under "/story/", defaulting to
+// the "intro" chapter.
 func customPathFn(r *http.Request) string {
 	path := strings.TrimSpace(r.URL.Path)
 	if path == "/story" || path == "/story/" {
-		path = "/story/start" // Custom default chapter
+		path = "/story/" + cyoa.IntroChapter // Default chapter
 	}
 	return path[len("/story/"):] // Remove leading "/story/"
 }
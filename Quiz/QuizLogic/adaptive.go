@@ -0,0 +1,126 @@
+package quiz
+
+import (
+	"math"
+)
+
+// Adaptive selection parameters.
+const (
+	startLevel  = 2.0  // Difficulty level the adaptive mode starts at
+	stepCorrect = 0.25 // Level increase for a correct answer
+	stepFast    = 0.5  // Level increase for a correct answer given within fastAnswer
+	stepStreak  = 0.5  // Extra increase once a streak reaches streakLength
+	stepMiss    = 1.0  // Level decrease for a wrong answer
+	skillRate   = 1.0  // Largest change of the skill estimate per answer
+
+	streakLength = 3 // Correct answers in a row that count as a streak
+)
+
+// selector decides which problem is asked next in a session.
+type selector interface {
+	// next returns the next problem to ask, and false once there are none left.
+	next() (Problem, bool)
+	// record tells the selector how the problem last returned by next was answered.
+	record(p Problem, o Outcome)
+}
+
+// sequential asks the problems in order.
+type sequential struct {
+	problems []Problem
+	i        int
+}
+
+func (s *sequential) next() (Problem, bool) {
+	if s.i >= len(s.problems) {
+		return Problem{}, false
+	}
+	s.i++
+	return s.problems[s.i-1], true
+}
+
+func (s *sequential) record(Problem, Outcome) {}
+
+// adaptive picks the unused problem whose difficulty is closest to the player's level.
+// The level rises with correct answers, faster for quick answers and streaks, and
+// drops after a miss. Alongside it keeps an estimate of the player's skill.
+type adaptive struct {
+	problems []Problem
+	used     []bool
+	level    float64
+	streak   int
+	skill    float64
+}
+
+// newAdaptive creates an adaptive selector over problems.
+func newAdaptive(problems []Problem) *adaptive {
+	return &adaptive{
+		problems: problems,
+		used:     make([]bool, len(problems)),
+		level:    startLevel,
+		skill:    DefaultDifficulty,
+	}
+}
+
+func (a *adaptive) next() (Problem, bool) {
+	best := -1
+	for i, p := range a.problems {
+		if a.used[i] {
+			continue
+		}
+		// Earlier problems win ties so equally rated problems keep their order
+		if best < 0 || math.Abs(float64(ratingOf(p))-a.level) < math.Abs(float64(ratingOf(a.problems[best]))-a.level) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Problem{}, false
+	}
+	a.used[best] = true
+	return a.problems[best], true
+}
+
+func (a *adaptive) record(p Problem, o Outcome) {
+	if o.Correct {
+		a.streak++
+		step := stepCorrect
+		if o.Elapsed <= fastAnswer {
+			step = stepFast
+		}
+		if a.streak >= streakLength {
+			step += stepStreak
+		}
+		a.level += step
+	} else {
+		a.streak = 0
+		a.level -= stepMiss
+	}
+	a.level = math.Max(MinDifficulty, math.Min(MaxDifficulty, a.level))
+
+	// Move the skill estimate by how surprising the outcome was for a problem of this
+	// difficulty: solving a hard problem raises it more than solving an easy one.
+	actual := 0.0
+	if o.Correct {
+		actual = 1
+	}
+	expected := 1 / (1 + math.Exp(float64(ratingOf(p))-a.skill))
+	a.skill += skillRate * (actual - expected)
+	a.skill = math.Max(MinDifficulty, math.Min(MaxDifficulty, a.skill))
+}
+
+// ratingOf returns the difficulty of p, or DefaultDifficulty if it isn't rated.
+func ratingOf(p Problem) int {
+	if p.Difficulty == 0 {
+		return DefaultDifficulty
+	}
+	return p.Difficulty
+}
+
+// difficultyOf returns the difficulty of the first problem with question q.
+func difficultyOf(problems []Problem, q string) int {
+	for _, p := range problems {
+		if p.Question == q {
+			return p.Difficulty
+		}
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ratedProblems returns two problems per difficulty level, easiest first.
+func ratedProblems() []Problem {
+	var problems []Problem
+	for d := MinDifficulty; d <= MaxDifficulty; d++ {
+		for i := 0; i < 2; i++ {
+			problems = append(problems, Problem{Question: strings.Repeat("q", d*10+i), Answer: "a", Difficulty: d})
+		}
+	}
+	return problems
+}
+
+func TestAdaptiveSelection(t *testing.T) {
+	a := newAdaptive(ratedProblems())
+
+	// Fast correct answers climb to the hardest problems.
+	var asked []int
+	for i := 0; i < 5; i++ {
+		p, _ := a.next()
+		asked = append(asked, p.Difficulty)
+		a.record(p, Outcome{Correct: true, Elapsed: time.Second})
+	}
+	if want := []int{2, 2, 3, 4, 5}; !equalInts(asked, want) {
+		t.Errorf("Expected difficulties %v after correct answers, but got %v", want, asked)
+	}
+	climbed := a.skill
+
+	// Misses bring the level back down.
+	asked = nil
+	for i := 0; i < 3; i++ {
+		p, _ := a.next()
+		asked = append(asked, p.Difficulty)
+		a.record(p, Outcome{Correct: false, Elapsed: time.Second})
+	}
+	if want := []int{5, 4, 3}; !equalInts(asked, want) {
+		t.Errorf("Expected difficulties %v after misses, but got %v", want, asked)
+	}
+	if a.skill >= climbed {
+		t.Errorf("Expected misses to lower the skill estimate below %v, but got %v", climbed, a.skill)
+	}
+
+	// Every problem is asked exactly once.
+	for n := 8; ; n++ {
+		if _, ok := a.next(); !ok {
+			if n != 10 {
+				t.Errorf("Expected 10 problems in total, but got %d", n)
+			}
+			break
+		}
+	}
+}
+
+func TestSessionAdaptive(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSession(ratedProblems(), WithAdaptive(), WithInput(strings.NewReader(strings.Repeat("a\n", 10))), WithOutput(&out))
+
+	res := s.Run(context.Background())
+	if res.Correct != 10 {
+		t.Errorf("Expected 10 correct answers, but got %+v", res)
+	}
+	if res.Skill <= DefaultDifficulty {
+		t.Errorf("Expected a skill estimate above %d after a perfect run, but got %v", DefaultDifficulty, res.Skill)
+	}
+	if !strings.Contains(out.String(), "Estimated skill level:") {
+		t.Errorf("Expected the skill estimate in the output, but got %q", out.String())
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
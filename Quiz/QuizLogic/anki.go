@@ -0,0 +1,140 @@
+package quiz
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ankiFieldSeparator separates the fields of a note in an Anki collection.
+const ankiFieldSeparator = "\x1f"
+
+// AnkiLoader loads problems from an Anki deck package (.apkg), a zip archive holding
+// the deck's SQLite collection. Each note becomes a problem with the HTML stripped
+// from its fields, and the note's tags become the problem's tags. Notes whose question
+// or answer field is empty once stripped (e.g. image-only cards) are skipped.
+type AnkiLoader struct {
+	QuestionField int // Index of the note field used as question, from 0
+	AnswerField   int // Index of the note field used as answer, from 0
+}
+
+// DefaultAnkiLoader maps the first note field (Front) to the question and the second
+// (Back) to the answer, as in Anki's basic note types.
+var DefaultAnkiLoader = AnkiLoader{QuestionField: 0, AnswerField: 1}
+
+var (
+	// ankiBreak matches tags that separate text, replaced by a space.
+	ankiBreak = regexp.MustCompile(`(?i)<br\s*/?>|</?(?:div|p|li|tr|td)[^>]*>`)
+	// ankiTag matches any other HTML tag, as well as sound references.
+	ankiTag = regexp.MustCompile(`<[^>]*>|\[sound:[^\]]*\]`)
+)
+
+// Load implements Loader for Anki deck packages.
+func (l AnkiLoader) Load(r io.Reader) ([]Problem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the provided Anki package: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the provided Anki package: %v", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	// Anki 2.1 packages keep a legacy collection.anki2 next to collection.anki21, and
+	// recent ones only a placeholder next to the unsupported collection.anki21b
+	collection, ok := files["collection.anki21"]
+	if !ok {
+		if _, ok := files["collection.anki21b"]; ok {
+			return nil, fmt.Errorf("unsupported Anki package format. Export the deck with \"Support older Anki versions\" enabled")
+		}
+		if collection, ok = files["collection.anki2"]; !ok {
+			return nil, fmt.Errorf("no collection found in the provided Anki package")
+		}
+	}
+
+	// The SQLite driver needs the collection as a file
+	tmp, err := os.CreateTemp("", "quiz-anki-*.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the Anki collection: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := extractZipFile(collection, tmp); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to extract the Anki collection: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to extract the Anki collection: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the Anki collection: %v", err)
+	}
+	defer db.Close()
+	return l.loadNotes(db)
+}
+
+// loadNotes reads every note of an Anki collection, in the order they were created.
+func (l AnkiLoader) loadNotes(db *sql.DB) ([]Problem, error) {
+	rows, err := db.Query(`SELECT flds, tags FROM notes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Anki notes: %v", err)
+	}
+	defer rows.Close()
+
+	var ret []Problem
+	for rows.Next() {
+		var fields, tags string
+		if err := rows.Scan(&fields, &tags); err != nil {
+			return nil, fmt.Errorf("failed to read the Anki notes: %v", err)
+		}
+		flds := strings.Split(fields, ankiFieldSeparator)
+		if l.QuestionField >= len(flds) || l.AnswerField >= len(flds) {
+			continue
+		}
+		p := Problem{
+			Question: StripHTML(flds[l.QuestionField]),
+			Answer:   StripHTML(flds[l.AnswerField]),
+			Tags:     ParseTags(strings.Join(strings.Fields(tags), "|")),
+		}
+		if p.Question != "" && p.Answer != "" {
+			ret = append(ret, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the Anki notes: %v", err)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no problems found in the provided Anki package")
+	}
+	return ret, nil
+}
+
+// extractZipFile copies the uncompressed contents of f to w.
+func extractZipFile(f *zip.File, w io.Writer) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// StripHTML turns an HTML note field into plain text: tags are removed, line breaks
+// and blocks become spaces, entities are decoded and whitespace is collapsed.
+func StripHTML(s string) string {
+	s = ankiBreak.ReplaceAllString(s, " ")
+	s = ankiTag.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
@@ -0,0 +1,106 @@
+package quiz
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// buildAnkiPackage creates an .apkg holding a collection with the given notes, each
+// a pair of fields and tags.
+func buildAnkiPackage(t *testing.T, name string, notes [][2]string) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("Failed to create the collection: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY, flds TEXT NOT NULL, tags TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Failed to create the notes table: %v", err)
+	}
+	for i, n := range notes {
+		if _, err := db.Exec(`INSERT INTO notes (id, flds, tags) VALUES (?, ?, ?)`, 1000-i, n[0], n[1]); err != nil {
+			t.Fatalf("Failed to insert note: %v", err)
+		}
+	}
+	db.Close()
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read the collection: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for file, data := range map[string][]byte{name: collection, "media": []byte("{}")} {
+		w, err := zw.Create(file)
+		if err != nil {
+			t.Fatalf("Failed to create %s in the package: %v", file, err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to write the package: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAnkiLoader(t *testing.T) {
+	// Notes are inserted with decreasing ids, so they load in reverse order.
+	data := buildAnkiPackage(t, "collection.anki2", [][2]string{
+		{"Capital of <b>France</b>?\x1fParis<br>(since 508)", " geography Europe "},
+		{"5&nbsp;+&nbsp;5\x1f<div>10</div>[sound:ten.mp3]", ""},
+		{"<img src=\"map.png\">\x1fSomewhere", "geography"},
+	})
+
+	problems, err := DefaultAnkiLoader.Load(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	expected := []Problem{
+		{Question: "5 + 5", Answer: "10"},
+		{Question: "Capital of France?", Answer: "Paris (since 508)", Tags: []string{"geography", "europe"}},
+	}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("Expected problems %+v, but got %+v", expected, problems)
+	}
+
+	// Fields can be mapped the other way around.
+	problems, err = AnkiLoader{QuestionField: 1, AnswerField: 0}.Load(bytes.NewReader(data))
+	if err != nil || len(problems) != 2 || problems[0].Question != "10" {
+		t.Errorf("Expected swapped fields, but got %+v, %v", problems, err)
+	}
+}
+
+func TestLoadFileAnkiFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deck.apkg")
+	data := buildAnkiPackage(t, "collection.anki2", [][2]string{{"Capital of France?\x1fParis", ""}})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write the package: %v", err)
+	}
+
+	problems, _, err := LoadFile(path, "", WithAnkiFields(1, 0))
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Question != "Paris" {
+		t.Errorf("Expected the second field as question, but got %+v", problems)
+	}
+}
+
+func TestAnkiLoaderErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"not a zip":     []byte("question,answer"),
+		"no collection": buildAnkiPackage(t, "notes.db", [][2]string{{"q\x1fa", ""}}),
+		"new format":    buildAnkiPackage(t, "collection.anki21b", [][2]string{{"q\x1fa", ""}}),
+	}
+
+	for name, data := range tests {
+		if _, err := DefaultAnkiLoader.Load(bytes.NewReader(data)); err == nil {
+			t.Errorf("Expected an error for %s, but got none", name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/text/message"
+)
+
+// ClockMode tells how a session shows the time left on its time limit.
+type ClockMode int
+
+const (
+	NoClock    ClockMode = iota // The time left is not shown
+	PlainClock                  // The time left is printed in front of each prompt
+	LiveClock                   // Like PlainClock, but redrawn every second with ANSI escapes, for terminals
+)
+
+// ANSI escapes used by the live clock
+const (
+	saveCursor    = "\x1b7"
+	restoreCursor = "\x1b8"
+	warnColor     = "\x1b[1;31m"
+	resetColor    = "\x1b[0m"
+	bell          = "\a"
+)
+
+// clock shows the time left on a session's countdown and warns the player when it
+// drops below the warning thresholds.
+type clock struct {
+	out      io.Writer
+	msg      *message.Printer
+	cd       *countdown
+	mode     ClockMode
+	width    int             // Width of the time shown, so that redraws line up
+	warnings []time.Duration // Thresholds not reached yet, longest first
+	warned   bool            // Whether a threshold was reached
+}
+
+// newClock creates a clock for cd writing to out with the printer msg. Warning
+// thresholds that are not below the time left are ignored.
+func newClock(out io.Writer, msg *message.Printer, cd *countdown, mode ClockMode, warnings []time.Duration) *clock {
+	left := cd.Remaining()
+	c := &clock{out: out, msg: msg, cd: cd, mode: mode, width: len(formatClock(left))}
+	for _, w := range warnings {
+		if w > 0 && w < left {
+			c.warnings = append(c.warnings, w)
+		}
+	}
+	sort.Slice(c.warnings, func(i, j int) bool {
+		return c.warnings[i] > c.warnings[j]
+	})
+	return c
+}
+
+// label returns the time left as shown in front of a prompt, in red on a live clock
+// once a warning was given.
+func (c *clock) label() string {
+	label := fmt.Sprintf("[%*s] ", c.width, formatClock(c.cd.Remaining()))
+	if c.mode == LiveClock && c.warned {
+		return warnColor + label + resetColor
+	}
+	return label
+}
+
+// prefix writes the time left in front of a prompt. It does nothing on a nil clock.
+func (c *clock) prefix() {
+	if c == nil || c.mode == NoClock {
+		return
+	}
+	fmt.Fprint(c.out, c.label())
+}
+
+// tick updates the clock, called once a second. A live clock is redrawn in place,
+// leaving what the player typed untouched, and rings the bell at each warning. Other
+// clocks print the warning on its own line, and tick reports whether they did so the
+// prompt can be repeated.
+func (c *clock) tick() bool {
+	var reached time.Duration
+	left := c.cd.Remaining()
+	for len(c.warnings) > 0 && left <= c.warnings[0] {
+		reached, c.warnings = c.warnings[0], c.warnings[1:]
+	}
+	if reached > 0 {
+		c.warned = true
+	}
+
+	if c.mode == LiveClock {
+		if reached > 0 {
+			fmt.Fprint(c.out, bell)
+		}
+		fmt.Fprint(c.out, saveCursor+"\r"+c.label()+restoreCursor)
+		return false
+	}
+	if reached > 0 {
+		c.msg.Fprintf(c.out, msgHurry, reached)
+		return true
+	}
+	return false
+}
+
+// formatClock formats d as minutes and seconds, with hours if needed. Seconds are
+// rounded up so that the clock only shows 0:00 once the time is up.
+func formatClock(d time.Duration) string {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
@@ -0,0 +1,66 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatClock(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{300 * time.Millisecond, "0:01"},
+		{30 * time.Second, "0:30"},
+		{90 * time.Second, "1:30"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+	for _, tt := range tests {
+		if got := formatClock(tt.d); got != tt.want {
+			t.Errorf("formatClock(%v) = %q; want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestSessionPlainClock(t *testing.T) {
+	// A pipe that is never written to blocks like an idle terminal.
+	r, w := io.Pipe()
+	defer w.Close()
+
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(r), WithOutput(&out), WithTimeLimit(1500*time.Millisecond),
+		WithClock(PlainClock), WithWarnings(time.Second, time.Minute))
+	s.Run(context.Background())
+
+	want := "[0:02] Problem #1: 5+5 = \nHurry up, 1s left!\n[0:01] Problem #1: 5+5 = \nTime's up!"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+	}
+	if strings.Contains(out.String(), "\x1b") {
+		t.Errorf("Expected no ANSI escapes in plain output, but got %q", out.String())
+	}
+}
+
+func TestSessionLiveClock(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(r), WithOutput(&out), WithTimeLimit(1500*time.Millisecond),
+		WithClock(LiveClock), WithWarnings(time.Second))
+	s.Run(context.Background())
+
+	// The clock is redrawn in red in front of the prompt without repeating it
+	want := "[0:02] Problem #1: 5+5 = " + bell + saveCursor + "\r" + warnColor + "[0:01] " + resetColor + restoreCursor
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+	}
+	if strings.Count(out.String(), "Problem #1") != 1 {
+		t.Errorf("Expected the prompt to be written once, but got %q", out.String())
+	}
+}
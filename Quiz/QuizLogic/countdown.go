@@ -0,0 +1,60 @@
+package quiz
+
+import (
+	"time"
+)
+
+// countdown is a timer for the time limit of a session that can be paused and
+// resumed, and tells how much time is left.
+type countdown struct {
+	timer    *time.Timer
+	deadline time.Time     // When the timer fires while running
+	left     time.Duration // Time left while paused
+	paused   bool
+}
+
+// newCountdown starts a countdown that fires after d.
+func newCountdown(d time.Duration) *countdown {
+	return &countdown{timer: time.NewTimer(d), deadline: time.Now().Add(d)}
+}
+
+// C returns the channel the current time is delivered on when the countdown runs out.
+func (c *countdown) C() <-chan time.Time {
+	return c.timer.C
+}
+
+// Pause stops the countdown until Resume is called. A countdown that already ran out
+// stays expired.
+func (c *countdown) Pause() {
+	if c.paused || !c.timer.Stop() {
+		return
+	}
+	c.left = time.Until(c.deadline)
+	c.paused = true
+}
+
+// Resume continues a paused countdown with the time that was left.
+func (c *countdown) Resume() {
+	if !c.paused {
+		return
+	}
+	c.deadline = time.Now().Add(c.left)
+	c.timer.Reset(c.left)
+	c.paused = false
+}
+
+// Remaining returns the time left before the countdown runs out.
+func (c *countdown) Remaining() time.Duration {
+	if c.paused {
+		return c.left
+	}
+	if left := time.Until(c.deadline); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// Stop releases the timer of the countdown.
+func (c *countdown) Stop() {
+	c.timer.Stop()
+}
@@ -0,0 +1,35 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountdownPause(t *testing.T) {
+	cd := newCountdown(50 * time.Millisecond)
+	defer cd.Stop()
+
+	cd.Pause()
+	left := cd.Remaining()
+	if left <= 0 || left > 50*time.Millisecond {
+		t.Fatalf("Expected up to 50ms left after pausing, but got %v", left)
+	}
+	select {
+	case <-cd.C():
+		t.Fatal("Expected a paused countdown not to fire")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if cd.Remaining() != left {
+		t.Errorf("Expected the remaining time to stay at %v while paused, but got %v", left, cd.Remaining())
+	}
+
+	cd.Resume()
+	select {
+	case <-cd.C():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the resumed countdown to fire")
+	}
+	if cd.Remaining() != 0 {
+		t.Errorf("Expected no time left, but got %v", cd.Remaining())
+	}
+}
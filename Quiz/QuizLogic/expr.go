@@ -0,0 +1,215 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep evaluating an answer cheap, whatever the player types.
+const (
+	maxExprLength = 200 // Longest expression evaluated, in bytes
+	maxExprDepth  = 32  // Deepest nesting of parentheses and signs
+)
+
+// exprParser is a recursive descent parser that evaluates arithmetic expressions made
+// of decimal numbers, + - * /, signs and parentheses. It knows no names or functions,
+// so an answer can't do anything but compute a number.
+type exprParser struct {
+	s     string
+	pos   int
+	depth int
+}
+
+// EvalExpr evaluates an arithmetic expression such as "2*5", "(1+1)/4" or "1e1".
+func EvalExpr(s string) (float64, error) {
+	if len(s) > maxExprLength {
+		return 0, fmt.Errorf("expression longer than %d characters", maxExprLength)
+	}
+	p := &exprParser{s: s}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if p.skipSpaces(); p.pos < len(p.s) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos+1)
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("expression out of range")
+	}
+	return v, nil
+}
+
+// expr parses a sum: term {("+" | "-") term}.
+func (p *exprParser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+', '-':
+			op := p.next()
+			w, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			if op == '+' {
+				v += w
+			} else {
+				v -= w
+			}
+		default:
+			return v, nil
+		}
+	}
+}
+
+// term parses a product: unary {("*" | "/") unary}.
+func (p *exprParser) term() (float64, error) {
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '*', '/':
+			op := p.next()
+			w, err := p.unary()
+			if err != nil {
+				return 0, err
+			}
+			if op == '*' {
+				v *= w
+			} else if w == 0 {
+				return 0, fmt.Errorf("division by zero")
+			} else {
+				v /= w
+			}
+		default:
+			return v, nil
+		}
+	}
+}
+
+// unary parses a signed operand: {"+" | "-"} (number | "(" expr ")").
+func (p *exprParser) unary() (float64, error) {
+	if p.depth++; p.depth > maxExprDepth {
+		return 0, fmt.Errorf("expression nested too deeply")
+	}
+	defer func() { p.depth-- }()
+
+	switch p.peek() {
+	case '+', '-':
+		sign := 1.0
+		if p.next() == '-' {
+			sign = -1
+		}
+		v, err := p.unary()
+		return sign * v, err
+	case '(':
+		p.next()
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.next()
+		return v, nil
+	default:
+		return p.number()
+	}
+}
+
+// number parses a decimal number with an optional fraction and exponent.
+func (p *exprParser) number() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	digits := func() {
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+	}
+	digits()
+	if p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		digits()
+	}
+	if p.pos > start && p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		digits()
+	}
+	if p.pos == start {
+		if p.pos == len(p.s) {
+			return 0, fmt.Errorf("unexpected end of expression")
+		}
+		return 0, fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos+1)
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.s[start:p.pos])
+	}
+	return v, nil
+}
+
+// peek returns the next character after any spaces without consuming it, or 0 at
+// the end of the expression.
+func (p *exprParser) peek() byte {
+	p.skipSpaces()
+	if p.pos == len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+// next consumes and returns the next character after any spaces.
+func (p *exprParser) next() byte {
+	c := p.peek()
+	p.pos++
+	return c
+}
+
+// skipSpaces moves past spaces and tabs.
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// sameValue reports whether the expression answer evaluates to the number want. The
+// values are compared up to floating point error only, so "0.1+0.2" is right for "0.3"
+// but "3.334" is wrong for "3.33".
+func sameValue(answer, want string) bool {
+	got, err := EvalExpr(answer)
+	if err != nil {
+		return false
+	}
+	wanted, err := strconv.ParseFloat(strings.TrimSpace(want), 64)
+	if err != nil {
+		return false
+	}
+	return math.Abs(got-wanted) <= 1e-9*math.Max(1, math.Abs(wanted))
+}
+
+// isEcho reports whether answer merely repeats a calculation from question, e.g. "5+5"
+// for "What is 5+5?". Only arithmetic expressions can be echoes, so plain numbers and
+// words such as "red" for "Which color, red or blue?" never are.
+func isEcho(answer, question string) bool {
+	compact := func(s string) string {
+		return strings.Join(strings.Fields(s), "")
+	}
+	answer = compact(answer)
+	if answer == "" || isNumber(answer) {
+		return false
+	}
+	if _, err := EvalExpr(answer); err != nil {
+		return false
+	}
+	return strings.Contains(compact(question), answer)
+}
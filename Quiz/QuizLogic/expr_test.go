@@ -0,0 +1,113 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvalExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"10", 10},
+		{"10.0", 10},
+		{"1e1", 10},
+		{"2*5", 10},
+		{" 2 * (3 + 2) ", 10},
+		{"-4+14", 10},
+		{"--10", 10},
+		{"20/2", 10},
+		{"1 - 2 - 3", -4},
+		{"2+3*4", 14},
+		{".5*20", 10},
+		{"2.5e-1*40", 10},
+	}
+	for _, tt := range tests {
+		got, err := EvalExpr(tt.expr)
+		if err != nil || got != tt.want {
+			t.Errorf("EvalExpr(%q) = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalExprErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"ten",
+		"2*",
+		"(1+2",
+		"1+2)",
+		"1/0",
+		"1/(2-2)",
+		"1e",
+		"os.Exit(1)",
+		"2^3",
+		"1e400",
+		strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100),
+		strings.Repeat("1+", 200) + "1",
+	}
+	for _, expr := range tests {
+		if v, err := EvalExpr(expr); err == nil {
+			t.Errorf("EvalExpr(%q) = %v; want an error", expr, v)
+		}
+	}
+}
+
+func TestExpressionMatching(t *testing.T) {
+	m := Matcher{Expressions: true}
+	tests := []struct {
+		answer, want string
+		ok           bool
+	}{
+		{"2*5", "10", true},
+		{"1e1", "10", true},
+		{"10.0", "10", true},
+		{"11", "10", false},
+		{"0.1+0.2", "0.3", true}, // Floating point error is tolerated
+		{"3.33", "3.33", true},
+		{"10/3", "3.33", false}, // Values are not rounded to the expected answer
+		{"3.3", "3.33", false},
+		{"10.4", "10", false},
+		{"0.25", "0.3", false},
+		{"4.54", "4.5", false},
+		{"2.45", "2.5", false},
+		{"3.334", "3.33", false},
+		{"10.04", "10.0", false},
+		{"ten", "10", false},
+		{"Paris", "Paris", true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.answer, tt.want); got != tt.ok {
+			t.Errorf("Match(%q, %q) = %v; want %v", tt.answer, tt.want, got, tt.ok)
+		}
+	}
+	if ExactMatcher.Match("2*5", "10") {
+		t.Error("Expected expressions to be rejected without Expressions")
+	}
+}
+
+func TestRejectEcho(t *testing.T) {
+	policy := ScoringPolicy{Match: Matcher{Expressions: true}, RejectEcho: true}
+	tests := []struct {
+		question, answer string
+		correct          bool
+	}{
+		{"5+5", "5+5", false},
+		{"What is 5 + 5?", "5 +5", false},
+		{"5+5", "2*5", true},
+		{"5+5", "10", true},
+		{"10/1", "10", true},
+	}
+	for _, tt := range tests {
+		if _, correct := policy.score(Problem{Question: tt.question, Answer: "10"}, tt.answer, false); correct != tt.correct {
+			t.Errorf("Answering %q to %q: correct = %v; want %v", tt.answer, tt.question, correct, tt.correct)
+		}
+	}
+
+	// Words from the question are not echoes
+	colors := Problem{Question: "Which color, red or blue?", Answer: "red"}
+	if points, correct := policy.score(colors, "red", false); !correct || points != 1 {
+		t.Errorf("Expected %q to be right for %q, but got %v points", "red", colors.Question, points)
+	}
+}
@@ -0,0 +1,142 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// MaxDigits is the largest operand size the generator supports.
+const MaxDigits = 9
+
+// difficultyDigits maps each difficulty level to its default operand size.
+var difficultyDigits = map[string]int{
+	"easy":   1,
+	"medium": 2,
+	"hard":   3,
+}
+
+// operators maps each generator operation to the symbol used in questions.
+var operators = map[string]string{
+	"add": "+",
+	"sub": "-",
+	"mul": "*",
+	"div": "/",
+}
+
+// GeneratorConfig configures the procedural arithmetic problem generator.
+// The zero values of AllowNegative and AllowFractions keep every answer a
+// non-negative integer.
+type GeneratorConfig struct {
+	Ops            []string // Operations to draw from: "add", "sub", "mul" and "div"
+	Digits         int      // Digits per operand; 0 uses the default of Difficulty
+	Difficulty     string   // "easy", "medium" or "hard"; empty means "easy"
+	Count          int      // Number of problems to generate
+	Seed           int64    // Seed for the random source, the same seed yields the same problems
+	AllowNegative  bool     // Allow subtractions with a negative result
+	AllowFractions bool     // Allow divisions with a remainder, answered to two decimal places
+}
+
+// Generate returns cfg.Count random arithmetic problems with their correct answers.
+func Generate(cfg GeneratorConfig) ([]Problem, error) {
+	if cfg.Count <= 0 {
+		return nil, fmt.Errorf("the number of problems to generate must be positive, got %d", cfg.Count)
+	}
+	if len(cfg.Ops) == 0 {
+		return nil, fmt.Errorf("no operations to generate problems from")
+	}
+	for _, op := range cfg.Ops {
+		if _, ok := operators[op]; !ok {
+			return nil, fmt.Errorf("unsupported operation: %q. Please use add, sub, mul or div", op)
+		}
+	}
+
+	digits := cfg.Digits
+	if digits == 0 {
+		difficulty := cfg.Difficulty
+		if difficulty == "" {
+			difficulty = "easy"
+		}
+		var ok bool
+		if digits, ok = difficultyDigits[difficulty]; !ok {
+			return nil, fmt.Errorf("unsupported difficulty: %q. Please use easy, medium or hard", cfg.Difficulty)
+		}
+	}
+	if digits < 1 || digits > MaxDigits {
+		return nil, fmt.Errorf("the number of digits must be between 1 and %d, got %d", MaxDigits, digits)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	lo, hi := operandRange(digits)
+	operand := func() int64 {
+		return lo + rng.Int63n(hi-lo+1)
+	}
+
+	ret := make([]Problem, cfg.Count)
+	for i := range ret {
+		op := cfg.Ops[rng.Intn(len(cfg.Ops))]
+		a, b := operand(), operand()
+		var answer string
+		switch op {
+		case "add":
+			answer = strconv.FormatInt(a+b, 10)
+		case "sub":
+			if !cfg.AllowNegative && a < b {
+				a, b = b, a
+			}
+			answer = strconv.FormatInt(a-b, 10)
+		case "mul":
+			answer = strconv.FormatInt(a*b, 10)
+		case "div":
+			if cfg.AllowFractions {
+				answer = strconv.FormatFloat(math.Round(float64(a)/float64(b)*100)/100, 'f', -1, 64)
+			} else {
+				// Build the dividend from the answer so the division has no remainder
+				a, answer = a*b, strconv.FormatInt(a, 10)
+			}
+		}
+		ret[i] = Problem{Question: fmt.Sprintf("%d%s%d", a, operators[op], b), Answer: answer, Difficulty: generatedDifficulty(op, digits), Tags: []string{op}}
+	}
+	return ret, nil
+}
+
+// Load implements Loader, so generated problems can be used wherever a question bank
+// is loaded. The reader is ignored.
+func (cfg GeneratorConfig) Load(io.Reader) ([]Problem, error) {
+	return Generate(cfg)
+}
+
+// ParseOps splits a comma separated list of operations, such as "add,sub,mul,div".
+func ParseOps(s string) []string {
+	var ops []string
+	for _, op := range strings.Split(s, ",") {
+		if op = strings.ToLower(strings.TrimSpace(op)); op != "" {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// generatedDifficulty rates a generated problem by its operand size, with
+// multiplication and division one step harder than addition and subtraction.
+func generatedDifficulty(op string, digits int) int {
+	d := digits
+	if op == "mul" || op == "div" {
+		d++
+	}
+	if d > MaxDifficulty {
+		d = MaxDifficulty
+	}
+	return d
+}
+
+// operandRange returns the smallest and largest operand with the given number of digits.
+// Single digit operands start at 1 so that divisions never divide by zero.
+func operandRange(digits int) (int64, int64) {
+	hi := int64(math.Pow10(digits)) - 1
+	lo := int64(math.Pow10(digits - 1))
+	return lo, hi
+}
@@ -0,0 +1,93 @@
+package quiz
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// evalGenerated computes the exact value of a generated "a<op>b" question.
+func evalGenerated(t *testing.T, q string) float64 {
+	t.Helper()
+	i := strings.IndexAny(q[1:], "+-*/") + 1
+	a, errA := strconv.ParseFloat(q[:i], 64)
+	b, errB := strconv.ParseFloat(q[i+1:], 64)
+	if errA != nil || errB != nil {
+		t.Fatalf("Malformed generated question %q", q)
+	}
+	switch q[i] {
+	case '+':
+		return a + b
+	case '-':
+		return a - b
+	case '*':
+		return a * b
+	default:
+		return a / b
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := GeneratorConfig{Ops: []string{"add", "sub", "mul", "div"}, Digits: 2, Count: 200, Seed: 42}
+	problems, err := Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if len(problems) != cfg.Count {
+		t.Fatalf("Expected %d problems, but got %d", cfg.Count, len(problems))
+	}
+
+	for _, p := range problems {
+		answer, err := strconv.ParseInt(p.Answer, 10, 64)
+		if err != nil {
+			t.Errorf("Expected an integer answer for %q, but got %q", p.Question, p.Answer)
+			continue
+		}
+		if answer < 0 {
+			t.Errorf("Expected a non-negative answer for %q, but got %d", p.Question, answer)
+		}
+		if want := evalGenerated(t, p.Question); float64(answer) != want {
+			t.Errorf("Expected %q = %v, but got %d", p.Question, want, answer)
+		}
+	}
+
+	// The same seed yields the same problems.
+	again, _ := Generate(cfg)
+	if !reflect.DeepEqual(problems, again) {
+		t.Fatalf("Expected the same problems for the same seed")
+	}
+}
+
+func TestGenerateFractions(t *testing.T) {
+	problems, err := Generate(GeneratorConfig{Ops: []string{"div"}, Difficulty: "medium", Count: 50, Seed: 1, AllowFractions: true})
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	for _, p := range problems {
+		answer, err := strconv.ParseFloat(p.Answer, 64)
+		if err != nil {
+			t.Fatalf("Expected a numeric answer for %q, but got %q", p.Question, p.Answer)
+		}
+		if want := math.Round(evalGenerated(t, p.Question)*100) / 100; answer != want {
+			t.Errorf("Expected %q = %v, but got %v", p.Question, want, answer)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []GeneratorConfig{
+		{Ops: []string{"add"}, Count: 0},
+		{Ops: []string{"pow"}, Count: 1},
+		{Count: 1},
+		{Ops: []string{"add"}, Count: 1, Difficulty: "insane"},
+		{Ops: []string{"add"}, Count: 1, Digits: MaxDigits + 1},
+	}
+
+	for _, cfg := range tests {
+		if _, err := Generate(cfg); err == nil {
+			t.Errorf("Expected an error for %+v, but got none", cfg)
+		}
+	}
+}
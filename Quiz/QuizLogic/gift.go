@@ -0,0 +1,277 @@
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GIFTLoader loads problems from a Moodle GIFT file. Short answer, numerical,
+// true/false, multiple choice and missing word questions are imported with their
+// first correct answer. Essay and matching questions, numerical ranges and
+// tolerances, and questions with only partially correct answers cannot be graded in
+// a text quiz and are skipped; Lint reports them. The last part of a $CATEGORY path
+// tags the questions below it.
+//
+// Example:
+//
+//	// Comments are ignored.
+//	$CATEGORY: $course$/Arithmetic
+//	::Sum:: 5+5 = {#10}
+//	What is the capital of France? {=Paris =paris}
+//	The sun rises in the east. {T}
+type GIFTLoader struct{}
+
+// giftSpecial lists the characters that must be escaped with a backslash in GIFT.
+const giftSpecial = `~=#{}:`
+
+// Load implements Loader for GIFT question banks.
+func (GIFTLoader) Load(r io.Reader) ([]Problem, error) {
+	problems, _, err := parseGIFT(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("no problems found in the provided GIFT file")
+	}
+	return problems, nil
+}
+
+// parseGIFT parses a GIFT file into problems, along with a warning for every question
+// that was skipped because it can't be graded as a single answer.
+func parseGIFT(r io.Reader) ([]Problem, []Issue, error) {
+	var (
+		ret      []Problem
+		issues   []Issue
+		block    []string // Lines of the question being read
+		start    int      // Line number where the current block starts
+		category []string // Tags from the last $CATEGORY line
+	)
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+		p, skip, err := parseGIFTQuestion(strings.Join(block, " "))
+		block = nil
+		switch {
+		case err != nil:
+			return fmt.Errorf("invalid GIFT format in question at line %d: %v", start, err)
+		case skip != "":
+			issues = append(issues, Issue{Line: start, Severity: Warning, Message: "question skipped: " + skip})
+		default:
+			p.Tags, p.Line = category, start
+			ret = append(ret, p)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "//"):
+			continue
+		case strings.HasPrefix(line, "$CATEGORY:"):
+			if err := flush(); err != nil {
+				return nil, nil, err
+			}
+			path := strings.TrimSpace(strings.TrimPrefix(line, "$CATEGORY:"))
+			category = ParseTags(path[strings.LastIndex(path, "/")+1:])
+		case line == "": // Questions are separated by blank lines
+			if err := flush(); err != nil {
+				return nil, nil, err
+			}
+		default:
+			if len(block) == 0 {
+				start = n
+			}
+			block = append(block, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the provided GIFT file: %v", err)
+	}
+	if err := flush(); err != nil {
+		return nil, nil, err
+	}
+	return ret, issues, nil
+}
+
+// parseGIFTQuestion parses a single GIFT question. If the question has no single
+// answer to grade against, it returns the reason to skip it instead.
+func parseGIFTQuestion(text string) (Problem, string, error) {
+	// Drop the optional "::title::" prefix
+	if strings.HasPrefix(text, "::") {
+		end := indexUnescaped(text[2:], "::")
+		if end < 0 {
+			return Problem{}, "", fmt.Errorf("unterminated question title")
+		}
+		text = strings.TrimSpace(text[2+end+2:])
+	}
+	// Drop the optional text format marker
+	for _, format := range []string{"[html]", "[moodle]", "[plain]", "[markdown]"} {
+		text = strings.TrimPrefix(text, format)
+	}
+
+	lbrace := indexUnescaped(text, "{")
+	if lbrace < 0 {
+		return Problem{}, "", fmt.Errorf("missing answer section")
+	}
+	rbrace := indexUnescaped(text[lbrace:], "}")
+	if rbrace < 0 {
+		return Problem{}, "", fmt.Errorf("unterminated answer section")
+	}
+	rbrace += lbrace
+
+	before := strings.TrimSpace(text[:lbrace])
+	after := strings.TrimSpace(text[rbrace+1:])
+	question := before
+	if after != "" { // Missing word format: the answer goes in the blank
+		question = before + " _____ " + after
+	}
+
+	answer, skip, err := parseGIFTAnswer(strings.TrimSpace(text[lbrace+1 : rbrace]))
+	if err != nil || skip != "" {
+		return Problem{}, skip, err
+	}
+	return Problem{Question: unescapeGIFT(question), Answer: answer}, "", nil
+}
+
+// parseGIFTAnswer extracts the correct answer from the contents of a GIFT answer
+// section, or the reason the question can't be graded.
+func parseGIFTAnswer(section string) (string, string, error) {
+	switch upper := strings.ToUpper(stripGIFTFeedback(section)); {
+	case section == "":
+		return "", "essay questions can't be graded", nil
+	case upper == "T" || upper == "TRUE":
+		return "true", "", nil
+	case upper == "F" || upper == "FALSE":
+		return "false", "", nil
+	case strings.HasPrefix(section, "#"): // Numerical question
+		return parseGIFTNumber(section[1:])
+	}
+
+	var (
+		fallback string // Best partially weighted choice, used if no choice is marked '='
+		partial  bool   // Whether some choice gives partial credit
+	)
+	for _, choice := range splitGIFTChoices(section) {
+		marker, body := choice[0], strings.TrimSpace(stripGIFTFeedback(choice[1:]))
+		if marker == '=' && indexUnescaped(body, "->") >= 0 {
+			return "", "matching questions can't be graded", nil
+		}
+		weight := ""
+		if strings.HasPrefix(body, "%") {
+			if end := strings.Index(body[1:], "%"); end >= 0 {
+				weight, body = body[1:end+1], strings.TrimSpace(body[end+2:])
+			}
+		}
+		switch {
+		case marker == '=':
+			return unescapeGIFT(body), "", nil
+		case weight == "100" && fallback == "":
+			fallback = unescapeGIFT(body)
+		case weight != "" && !strings.HasPrefix(weight, "-") && strings.Trim(weight, "0.") != "":
+			partial = true
+		}
+	}
+	switch {
+	case fallback != "":
+		return fallback, "", nil
+	case partial:
+		return "", "only partially correct answers in " + strconv.Quote(section), nil
+	}
+	return "", "", fmt.Errorf("no correct answer in %q", section)
+}
+
+// parseGIFTNumber extracts the expected value from a numerical answer such as "10",
+// "10:0" or "=10 =%50%9". Ranges such as "1..5" and answers with a tolerance such as
+// "10:0.5" accept more than one value, so it returns the reason to skip them instead.
+func parseGIFTNumber(section string) (string, string, error) {
+	section = strings.TrimSpace(section)
+	if strings.HasPrefix(section, "=") {
+		section = splitGIFTChoices(section)[0][1:]
+	}
+	section = strings.TrimSpace(stripGIFTFeedback(section))
+	if strings.Contains(section, "..") {
+		return "", "numerical ranges can't be graded as a single answer", nil
+	}
+	if i := strings.Index(section, ":"); i >= 0 {
+		if tolerance, err := strconv.ParseFloat(strings.TrimSpace(section[i+1:]), 64); err != nil || tolerance != 0 {
+			return "", "numerical answers with a tolerance can't be graded as a single answer", nil
+		}
+		section = section[:i]
+	}
+	if section == "" {
+		return "", "", fmt.Errorf("missing numerical answer")
+	}
+	return strings.TrimSpace(section), "", nil
+}
+
+// splitGIFTChoices splits an answer section into choices, each starting with its
+// unescaped '=' or '~' marker.
+func splitGIFTChoices(section string) []string {
+	var choices []string
+	start := -1
+	for i := 0; i < len(section); i++ {
+		switch section[i] {
+		case '\\':
+			i++ // Skip the escaped character
+		case '=', '~':
+			if start >= 0 {
+				choices = append(choices, section[start:i])
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		choices = append(choices, section[start:])
+	}
+	return choices
+}
+
+// stripGIFTFeedback removes the "#feedback" suffix from an answer choice.
+func stripGIFTFeedback(s string) string {
+	if i := indexUnescaped(s, "#"); i > 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// indexUnescaped returns the index of the first occurrence of sep in s that is
+// not preceded by a backslash, or -1 if there is none.
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeGIFT resolves GIFT escape sequences and trims the result.
+func unescapeGIFT(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch next := s[i+1]; {
+			case next == 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case strings.IndexByte(giftSpecial, next) >= 0 || next == '\\':
+				b.WriteByte(next)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
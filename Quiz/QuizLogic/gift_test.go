@@ -0,0 +1,99 @@
+package quiz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGIFTLoader(t *testing.T) {
+	input := `// Arithmetic and trivia
+$CATEGORY: $course$/Sample
+
+::Numeric:: 7+3 = {#10:0}
+
+::Range:: Pick a number between 1 and 5 {#1..5}
+
+The sun rises in the east. {T}
+
+The sun rises in the west. {FALSE#It rises in the east.}
+
+Mahatma Gandhi was born in {=1869} in India.
+
+Which planet is the largest? {~Mars ~Earth =Jupiter#Correct!}
+
+Weighted { ~%50%close ~%100%right ~wrong }
+
+Escaped 1\=1? {=yes\: really}
+
+Write an essay about Go. {}
+
+Match the capitals { =France -> Paris =Italy -> Rome }
+
+Within half a unit of 10 {#10:0.5}
+
+Only halves { ~%50%A ~%50%B }
+`
+	sample := []string{"sample"}
+	expected := []Problem{
+		{Question: "7+3 =", Answer: "10", Tags: sample, Line: 4},
+		{Question: "The sun rises in the east.", Answer: "true", Tags: sample, Line: 8},
+		{Question: "The sun rises in the west.", Answer: "false", Tags: sample, Line: 10},
+		{Question: "Mahatma Gandhi was born in _____ in India.", Answer: "1869", Tags: sample, Line: 12},
+		{Question: "Which planet is the largest?", Answer: "Jupiter", Tags: sample, Line: 14},
+		{Question: "Weighted", Answer: "right", Tags: sample, Line: 16},
+		{Question: "Escaped 1=1?", Answer: "yes: really", Tags: sample, Line: 18},
+	}
+
+	problems, err := GIFTLoader{}.Load(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %d problems, but got %d: %v", len(expected), len(problems), problems)
+	}
+	for i, problem := range problems {
+		if !reflect.DeepEqual(problem, expected[i]) {
+			t.Errorf("Expected problem %v, but got %v", expected[i], problem)
+		}
+	}
+}
+
+func TestLintGIFTSkipped(t *testing.T) {
+	input := "Pick a number {#1..5}\n\n7+3 = {#10:0}\n\nWithin half of 10 {#10:0.5}\n\nOnly halves { ~%50%A ~%50%B }\n\nAn essay {}\n"
+	expected := []string{
+		"line 1: warning: question skipped: numerical ranges can't be graded as a single answer",
+		"line 5: warning: question skipped: numerical answers with a tolerance can't be graded as a single answer",
+		"line 7: warning: question skipped: only partially correct answers in \"~%50%A ~%50%B\"",
+		"line 9: warning: question skipped: essay questions can't be graded",
+	}
+
+	issues, err := Lint([]byte(input), "gift")
+	if err != nil {
+		t.Fatalf("Lint returned error: %v", err)
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, but got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("Expected issue %q, but got %q", expected[i], issue)
+		}
+	}
+}
+
+func TestGIFTLoaderErrors(t *testing.T) {
+	tests := []string{
+		"No answer section here",
+		"Unterminated {=answer",
+		"Only wrong choices {~a ~b}",
+		"Only a range {#1..5}",
+		"// Only a comment",
+	}
+
+	for _, input := range tests {
+		if _, err := (GIFTLoader{}).Load(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error for %q, but got none", input)
+		}
+	}
+}
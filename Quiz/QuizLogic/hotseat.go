@@ -0,0 +1,85 @@
+package quiz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/text/message"
+)
+
+// Standing is the result of one player, or team, in a hot-seat game.
+type Standing struct {
+	Player string
+	Result
+}
+
+// RunHotSeat lets players take turns on one terminal, each answering the same problems
+// in a session configured by opts. Every turn has the full time limit, which only
+// starts once the player presses Enter. Saving is disabled, since a game can't be
+// resumed. The game ends early if the input runs out or ctx is cancelled.
+//
+// It prints a scoreboard at the end and returns the standings of the players who
+// took their turn, best first: by score, then correct answers, then time.
+func RunHotSeat(ctx context.Context, players []string, problems []Problem, opts ...SessionOption) []Standing {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Stops the input reader once the game is over
+
+	// A single reader for all turns, so no turn swallows the next one's input
+	first := NewSession(problems, opts...)
+	answers := readAnswers(ctx, first.in)
+	out, msg := first.out, first.msg
+
+	var standings []Standing
+	for i, player := range players {
+		msg.Fprintf(out, msgTurn, player, i+1, len(players))
+		if !waitForEnter(ctx, answers) {
+			fmt.Fprintln(out)
+			break
+		}
+		s := NewSession(problems, opts...)
+		s.savePath = ""
+		s.answers = answers
+		standings = append(standings, Standing{Player: player, Result: s.Run(ctx)})
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		return a.Duration < b.Duration
+	})
+	if len(standings) > 0 {
+		msg.Fprintf(out, msgScoreboard)
+		printStandings(out, msg, standings)
+	}
+	return standings
+}
+
+// waitForEnter waits for a line of input. It returns false if the input ran out or
+// ctx was cancelled first.
+func waitForEnter(ctx context.Context, answers <-chan string) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case _, ok := <-answers:
+		return ok
+	}
+}
+
+// printStandings writes the standings of a hot-seat game as a numbered list.
+func printStandings(w io.Writer, msg *message.Printer, standings []Standing) {
+	for i, s := range standings {
+		msg.Fprintf(w, msgStanding, i+1, s.Player, s.Correct, s.Total,
+			roundPoints(s.Score), roundPoints(s.MaxScore), s.Duration.Round(100*time.Millisecond))
+	}
+}
@@ -0,0 +1,48 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunHotSeat(t *testing.T) {
+	// Each turn starts with an empty line for Enter
+	input := "\n10\n3\n10\n" + "\n10\n2\n10\n" + "\n1\n"
+	var out bytes.Buffer
+	standings := RunHotSeat(context.Background(), []string{"Ann", "Ben", "Cid"}, sessionProblems,
+		WithInput(strings.NewReader(input)), WithOutput(&out), WithSaveFile("unused.json"))
+
+	if len(standings) != 3 {
+		t.Fatalf("Expected 3 standings, but got %+v", standings)
+	}
+	want := []struct {
+		player  string
+		correct int
+	}{{"Ben", 3}, {"Ann", 2}, {"Cid", 0}}
+	for i, w := range want {
+		if standings[i].Player != w.player || standings[i].Correct != w.correct {
+			t.Errorf("Expected %s with %d correct in place %d, but got %+v", w.player, w.correct, i+1, standings[i])
+		}
+	}
+	// Every player answers the full set in their own session
+	if len(standings[0].Outcomes) != 3 || standings[2].Outcomes[0].Answer != "1" {
+		t.Errorf("Expected separate outcomes per player, but got %+v", standings)
+	}
+	for _, want := range []string{"== Ann's turn (1 of 3) ==", "== Cid's turn (3 of 3) ==", "== Scoreboard ==\n  1. Ben - 3/3 correct"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+		}
+	}
+}
+
+func TestRunHotSeatInputClosed(t *testing.T) {
+	var out bytes.Buffer
+	standings := RunHotSeat(context.Background(), []string{"Ann", "Ben"}, sessionProblems,
+		WithInput(strings.NewReader("\n10\n2\n10\n")), WithOutput(&out))
+
+	if len(standings) != 1 || standings[0].Player != "Ann" || standings[0].Correct != 3 {
+		t.Errorf("Expected only Ann's turn to be played, but got %+v", standings)
+	}
+}
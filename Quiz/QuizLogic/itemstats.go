@@ -0,0 +1,159 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Item analysis parameters.
+const (
+	MinDiscriminationRuns = 5    // Runs of a quiz needed before the discrimination index is computed
+	discriminationGroup   = 0.27 // Share of the best and worst runs compared by the discrimination index
+)
+
+// ItemStat holds the statistics of a single question across the recorded runs of a quiz.
+type ItemStat struct {
+	Question       string  `json:"question"`
+	Seen           int     `json:"seen"`            // Times the question was asked
+	Correct        int     `json:"correct"`         // Times it was answered correctly
+	PercentCorrect float64 `json:"percent_correct"` // Correct answers per time asked, from 0 to 100
+	MeanSeconds    float64 `json:"mean_seconds"`    // Mean response time of the answers given
+	Skipped        int     `json:"skipped"`         // Times it was answered with an empty line
+	TimedOut       int     `json:"timed_out"`       // Times the time limit ran out while it was asked
+	// Discrimination is the share of the best runs that answered correctly minus the
+	// share of the worst runs, from -1 to 1. Good questions are answered correctly by
+	// strong players and missed by weak ones; values near 0 or below point to broken
+	// or misleading questions. Nil with fewer than MinDiscriminationRuns runs.
+	Discrimination *float64 `json:"discrimination,omitempty"`
+}
+
+// runScore is a recorded run ranked for the discrimination index.
+type runScore struct {
+	id       int64
+	score    float64 // Share of correct answers
+	duration int64
+}
+
+// ItemStats computes per-question statistics over every recorded run of a quiz, in the
+// order the questions were first asked.
+func (l *Leaderboard) ItemStats(quizHash string) ([]ItemStat, error) {
+	rows, err := l.db.Query(`SELECT id, correct, total, duration_ms FROM runs WHERE quiz_hash = ?`, quizHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quiz runs: %v", err)
+	}
+	var runs []runScore
+	for rows.Next() {
+		var (
+			r              runScore
+			correct, total int
+		)
+		if err := rows.Scan(&r.id, &correct, &total, &r.duration); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read quiz runs: %v", err)
+		}
+		if total > 0 {
+			r.score = float64(correct) / float64(total)
+		}
+		runs = append(runs, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quiz runs: %v", err)
+	}
+
+	rows, err = l.db.Query(`
+		SELECT a.run_id, a.question, a.answer, a.correct, a.elapsed_ms, a.timed_out
+		FROM answers a JOIN runs r ON r.id = a.run_id
+		WHERE r.quiz_hash = ? ORDER BY a.run_id, a.position`, quizHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query answers: %v", err)
+	}
+	defer rows.Close()
+
+	var (
+		stats     []*ItemStat
+		byText    = make(map[string]*ItemStat)
+		totalMs   = make(map[string]int64)
+		correctIn = make(map[string]map[int64]bool) // Runs that answered each question correctly
+	)
+	for rows.Next() {
+		var (
+			runID, elapsedMs  int64
+			question, answer  string
+			correct, timedOut bool
+		)
+		if err := rows.Scan(&runID, &question, &answer, &correct, &elapsedMs, &timedOut); err != nil {
+			return nil, fmt.Errorf("failed to read answers: %v", err)
+		}
+		st, ok := byText[question]
+		if !ok {
+			st = &ItemStat{Question: question}
+			byText[question] = st
+			correctIn[question] = make(map[int64]bool)
+			stats = append(stats, st)
+		}
+		st.Seen++
+		switch {
+		case timedOut:
+			st.TimedOut++
+		case strings.TrimSpace(answer) == "":
+			st.Skipped++
+		}
+		if !timedOut {
+			totalMs[question] += elapsedMs
+		}
+		if correct {
+			st.Correct++
+			correctIn[question][runID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read answers: %v", err)
+	}
+
+	upper, lower := discriminationGroups(runs)
+	ret := make([]ItemStat, len(stats))
+	for i, st := range stats {
+		st.PercentCorrect = 100 * float64(st.Correct) / float64(st.Seen)
+		if answered := st.Seen - st.TimedOut; answered > 0 {
+			st.MeanSeconds = (time.Duration(totalMs[st.Question]/int64(answered)) * time.Millisecond).Seconds()
+		}
+		if upper != nil {
+			d := shareCorrect(upper, correctIn[st.Question]) - shareCorrect(lower, correctIn[st.Question])
+			st.Discrimination = &d
+		}
+		ret[i] = *st
+	}
+	return ret, nil
+}
+
+// discriminationGroups returns the best and the worst runs compared by the
+// discrimination index, or nil if there are fewer than MinDiscriminationRuns runs.
+func discriminationGroups(runs []runScore) (upper, lower []runScore) {
+	if len(runs) < MinDiscriminationRuns {
+		return nil, nil
+	}
+	ranked := append([]runScore(nil), runs...)
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].duration < ranked[j].duration
+	})
+	n := int(math.Ceil(discriminationGroup * float64(len(ranked))))
+	return ranked[:n], ranked[len(ranked)-n:]
+}
+
+// shareCorrect returns the share of runs in group that answered correctly.
+func shareCorrect(group []runScore, correct map[int64]bool) float64 {
+	n := 0
+	for _, r := range group {
+		if correct[r.id] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(group))
+}
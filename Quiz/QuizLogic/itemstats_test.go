@@ -0,0 +1,73 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItemStats(t *testing.T) {
+	l := newTestLeaderboard(t)
+	hash := HashQuiz([]byte("items"))
+	easy := func() Outcome { return Outcome{Question: "1+1", Answer: "2", Correct: true, Elapsed: time.Second} }
+	good := func(correct bool) Outcome {
+		return Outcome{Question: "12*12", Answer: "144", Correct: correct, Elapsed: 3 * time.Second}
+	}
+
+	// Runs from best to worst; the discriminating question is only solved by the best two.
+	runs := [][]Outcome{
+		{easy(), good(true), {Question: "7*8", Answer: "56", Correct: true, Elapsed: 2 * time.Second}},
+		{easy(), good(true), {Question: "7*8", Answer: " ", Elapsed: 4 * time.Second}},
+		{easy(), good(false), {Question: "7*8", Answer: "56", Correct: true, Elapsed: 6 * time.Second}},
+		{easy(), good(false), {Question: "7*8", Elapsed: 10 * time.Second, TimedOut: true}},
+		{easy(), good(false), {Question: "7*8", Elapsed: 10 * time.Second, TimedOut: true}},
+	}
+	for i, outcomes := range runs {
+		run := Run{Player: "raz", QuizHash: hash, QuizName: "items.csv", Total: 3, Duration: time.Duration(i+1) * time.Minute, Outcomes: outcomes}
+		for _, o := range outcomes {
+			if o.Correct {
+				run.Correct++
+			}
+		}
+		if err := l.Record(run); err != nil {
+			t.Fatalf("Record returned error: %v", err)
+		}
+	}
+
+	stats, err := l.ItemStats(hash)
+	if err != nil {
+		t.Fatalf("ItemStats returned error: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 questions, but got %+v", stats)
+	}
+
+	tests := []struct {
+		question       string
+		seen, correct  int
+		percent, mean  float64
+		skipped, timed int
+		discrimination float64
+	}{
+		{"1+1", 5, 5, 100, 1, 0, 0, 0},
+		{"12*12", 5, 2, 40, 3, 0, 0, 1},
+		{"7*8", 5, 2, 40, 4, 1, 2, 0.5},
+	}
+	for i, tt := range tests {
+		st := stats[i]
+		if st.Question != tt.question || st.Seen != tt.seen || st.Correct != tt.correct || st.PercentCorrect != tt.percent ||
+			st.MeanSeconds != tt.mean || st.Skipped != tt.skipped || st.TimedOut != tt.timed {
+			t.Errorf("Expected %+v, but got %+v", tt, st)
+		}
+		if st.Discrimination == nil || *st.Discrimination != tt.discrimination {
+			t.Errorf("Expected discrimination %v for %q, but got %v", tt.discrimination, tt.question, st.Discrimination)
+		}
+	}
+
+	// Too few runs for a discrimination index.
+	other := HashQuiz([]byte("other"))
+	l.Record(Run{Player: "raz", QuizHash: other, QuizName: "other.csv", Correct: 1, Total: 1, Outcomes: []Outcome{easy()}})
+	stats, err = l.ItemStats(other)
+	if err != nil || len(stats) != 1 || stats[0].Discrimination != nil {
+		t.Errorf("Expected no discrimination index for a single run, but got %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,216 @@
+package quiz
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // Import the pure Go SQLite driver
+)
+
+// Run represents a finished quiz run as stored in the leaderboard.
+type Run struct {
+	Player     string
+	QuizHash   string  // Hash of the question bank, see HashQuiz
+	QuizName   string  // Display name of the question bank, usually its file name
+	Score      float64 // Points scored under the scoring policy, see Result
+	Correct    int
+	Total      int
+	Duration   time.Duration
+	FinishedAt time.Time
+	Outcomes   []Outcome // Per-question outcomes for item analysis, not returned by queries
+}
+
+// QuizSummary describes a question bank that has runs on the leaderboard.
+type QuizSummary struct {
+	Hash string
+	Name string
+	Runs int
+}
+
+// Leaderboard stores finished quiz runs in a SQLite database.
+type Leaderboard struct {
+	db *sql.DB
+}
+
+// leaderboardSchema creates the runs and answers tables if they don't exist yet.
+const leaderboardSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	player      TEXT    NOT NULL,
+	quiz_hash   TEXT    NOT NULL,
+	quiz_name   TEXT    NOT NULL,
+	score       REAL    NOT NULL DEFAULT 0,
+	correct     INTEGER NOT NULL,
+	total       INTEGER NOT NULL,
+	duration_ms INTEGER NOT NULL,
+	finished_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_by_player ON runs (player);
+CREATE TABLE IF NOT EXISTS answers (
+	run_id     INTEGER NOT NULL REFERENCES runs (id),
+	position   INTEGER NOT NULL,
+	question   TEXT    NOT NULL,
+	answer     TEXT    NOT NULL,
+	correct    INTEGER NOT NULL,
+	elapsed_ms INTEGER NOT NULL,
+	timed_out  INTEGER NOT NULL,
+	PRIMARY KEY (run_id, position)
+);`
+
+// leaderboardIndex creates the index that ranks the runs of a quiz, in the order of
+// rankedRuns. It is created after migrateRuns, which adds the score column.
+const leaderboardIndex = `CREATE INDEX IF NOT EXISTS runs_by_quiz ON runs (quiz_hash, score DESC, correct DESC, duration_ms)`
+
+// rankedRuns orders runs from best to worst: highest score first, then most correct
+// answers, then fastest.
+const rankedRuns = `ORDER BY score DESC, correct DESC, duration_ms ASC, finished_at ASC`
+
+// NewLeaderboard returns a Leaderboard backed by db, creating its tables if needed and
+// upgrading those of older versions.
+func NewLeaderboard(db *sql.DB) (*Leaderboard, error) {
+	if _, err := db.Exec(leaderboardSchema); err != nil {
+		return nil, fmt.Errorf("failed to create the leaderboard tables: %v", err)
+	}
+	if err := migrateRuns(db); err != nil {
+		return nil, fmt.Errorf("failed to upgrade the leaderboard tables: %v", err)
+	}
+	if _, err := db.Exec(leaderboardIndex); err != nil {
+		return nil, fmt.Errorf("failed to create the leaderboard tables: %v", err)
+	}
+	return &Leaderboard{db: db}, nil
+}
+
+// migrateRuns adds the score column to a runs table created before runs were ranked
+// by score. Older runs were scored one point per correct answer, so their score is
+// their number of correct answers. The index on the old ranking is dropped.
+func migrateRuns(db *sql.DB) error {
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('runs') WHERE name = 'score'`).Scan(&n); err != nil || n > 0 {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once committed
+
+	for _, stmt := range []string{
+		`ALTER TABLE runs ADD COLUMN score REAL NOT NULL DEFAULT 0`,
+		`UPDATE runs SET score = correct`,
+		`DROP INDEX IF EXISTS runs_by_quiz`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// HashQuiz returns a short, stable identifier for the contents of a question bank,
+// so runs of the same quiz are grouped together even if the file is renamed.
+func HashQuiz(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Record stores a finished run along with its per-question outcomes.
+func (l *Leaderboard) Record(r Run) error {
+	tx, err := l.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to record the quiz run: %v", err)
+	}
+	defer tx.Rollback() // No-op once committed
+
+	res, err := tx.Exec(
+		"INSERT INTO runs (player, quiz_hash, quiz_name, score, correct, total, duration_ms, finished_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		r.Player, r.QuizHash, r.QuizName, r.Score, r.Correct, r.Total, r.Duration.Milliseconds(), r.FinishedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record the quiz run: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to record the quiz run: %v", err)
+	}
+	for i, o := range r.Outcomes {
+		_, err := tx.Exec(
+			"INSERT INTO answers (run_id, position, question, answer, correct, elapsed_ms, timed_out) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			id, i, o.Question, o.Answer, o.Correct, o.Elapsed.Milliseconds(), o.TimedOut,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record the answers of the quiz run: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record the quiz run: %v", err)
+	}
+	return nil
+}
+
+// Quizzes returns every question bank with recorded runs, most played first.
+func (l *Leaderboard) Quizzes() ([]QuizSummary, error) {
+	rows, err := l.db.Query(`
+		SELECT quiz_hash, MAX(quiz_name), COUNT(*) FROM runs
+		GROUP BY quiz_hash ORDER BY COUNT(*) DESC, MAX(quiz_name)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quizzes: %v", err)
+	}
+	defer rows.Close()
+
+	var ret []QuizSummary
+	for rows.Next() {
+		var q QuizSummary
+		if err := rows.Scan(&q.Hash, &q.Name, &q.Runs); err != nil {
+			return nil, fmt.Errorf("failed to list quizzes: %v", err)
+		}
+		ret = append(ret, q)
+	}
+	return ret, rows.Err()
+}
+
+// Top returns the best runs of a quiz, at most limit of them.
+func (l *Leaderboard) Top(quizHash string, limit int) ([]Run, error) {
+	rows, err := l.db.Query(`
+		SELECT player, quiz_hash, quiz_name, score, correct, total, duration_ms, finished_at FROM runs
+		WHERE quiz_hash = ? `+rankedRuns+` LIMIT ?`, quizHash, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top scores: %v", err)
+	}
+	return scanRuns(rows)
+}
+
+// PersonalBests returns the best run of player for every quiz they played.
+func (l *Leaderboard) PersonalBests(player string) ([]Run, error) {
+	rows, err := l.db.Query(`
+		SELECT player, quiz_hash, quiz_name, score, correct, total, duration_ms, finished_at FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY quiz_hash `+rankedRuns+`) AS rank
+			FROM runs WHERE player = ?
+		) WHERE rank = 1 ORDER BY quiz_name`, player)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query personal bests: %v", err)
+	}
+	return scanRuns(rows)
+}
+
+// scanRuns reads all rows of a runs query and closes them.
+func scanRuns(rows *sql.Rows) ([]Run, error) {
+	defer rows.Close()
+
+	var ret []Run
+	for rows.Next() {
+		var (
+			r                    Run
+			durationMs, finished int64
+		)
+		if err := rows.Scan(&r.Player, &r.QuizHash, &r.QuizName, &r.Score, &r.Correct, &r.Total, &durationMs, &finished); err != nil {
+			return nil, fmt.Errorf("failed to read quiz runs: %v", err)
+		}
+		r.Duration = time.Duration(durationMs) * time.Millisecond
+		r.FinishedAt = time.Unix(finished, 0)
+		ret = append(ret, r)
+	}
+	return ret, rows.Err()
+}
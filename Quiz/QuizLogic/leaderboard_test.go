@@ -0,0 +1,139 @@
+package quiz
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// newTestLeaderboard returns a Leaderboard backed by an in-memory database.
+func newTestLeaderboard(t *testing.T) *Leaderboard {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1) // Every connection would get its own in-memory database
+	t.Cleanup(func() { db.Close() })
+
+	l, err := NewLeaderboard(db)
+	if err != nil {
+		t.Fatalf("NewLeaderboard returned error: %v", err)
+	}
+	return l
+}
+
+func TestLeaderboard(t *testing.T) {
+	l := newTestLeaderboard(t)
+	now := time.Unix(1700000000, 0)
+	arithmetic, capitals := HashQuiz([]byte("5+5,10\n")), HashQuiz([]byte("France,Paris\n"))
+
+	runs := []Run{
+		{Player: "raz", QuizHash: arithmetic, QuizName: "test.csv", Correct: 8, Total: 10, Duration: 20 * time.Second},
+		{Player: "raz", QuizHash: arithmetic, QuizName: "test.csv", Correct: 9, Total: 10, Duration: 25 * time.Second},
+		{Player: "dana", QuizHash: arithmetic, QuizName: "test.csv", Correct: 9, Total: 10, Duration: 15 * time.Second},
+		{Player: "raz", QuizHash: capitals, QuizName: "capitals.csv", Correct: 3, Total: 5, Duration: 10 * time.Second},
+	}
+	for _, r := range runs {
+		r.FinishedAt = now
+		if err := l.Record(r); err != nil {
+			t.Fatalf("Record returned error: %v", err)
+		}
+	}
+
+	top, err := l.Top(arithmetic, 2)
+	if err != nil {
+		t.Fatalf("Top returned error: %v", err)
+	}
+	if len(top) != 2 || top[0].Player != "dana" || top[1].Player != "raz" || top[1].Correct != 9 {
+		t.Errorf("Expected dana then raz with 9 correct, but got %+v", top)
+	}
+	if !top[0].FinishedAt.Equal(now) || top[0].Duration != 15*time.Second {
+		t.Errorf("Expected the run time and duration to round-trip, but got %+v", top[0])
+	}
+
+	bests, err := l.PersonalBests("raz")
+	if err != nil {
+		t.Fatalf("PersonalBests returned error: %v", err)
+	}
+	if len(bests) != 2 || bests[0].QuizName != "capitals.csv" || bests[1].Correct != 9 {
+		t.Errorf("Expected one best run per quiz, but got %+v", bests)
+	}
+
+	quizzes, err := l.Quizzes()
+	if err != nil {
+		t.Fatalf("Quizzes returned error: %v", err)
+	}
+	if len(quizzes) != 2 || quizzes[0].Hash != arithmetic || quizzes[0].Runs != 3 {
+		t.Errorf("Expected the arithmetic quiz first with 3 runs, but got %+v", quizzes)
+	}
+}
+
+func TestLeaderboardRanksByScore(t *testing.T) {
+	l := newTestLeaderboard(t)
+	hash := HashQuiz([]byte("5+5,10\n"))
+
+	runs := []Run{
+		{Player: "raz", QuizHash: hash, QuizName: "test.csv", Score: 7.5, Correct: 9, Total: 10, Duration: 10 * time.Second},
+		{Player: "dana", QuizHash: hash, QuizName: "test.csv", Score: 8, Correct: 8, Total: 10, Duration: 30 * time.Second},
+	}
+	for _, r := range runs {
+		if err := l.Record(r); err != nil {
+			t.Fatalf("Record returned error: %v", err)
+		}
+	}
+
+	top, err := l.Top(hash, 2)
+	if err != nil {
+		t.Fatalf("Top returned error: %v", err)
+	}
+	if len(top) != 2 || top[0].Player != "dana" || top[0].Score != 8 || top[1].Score != 7.5 {
+		t.Errorf("Expected dana's higher score first, but got %+v", top)
+	}
+}
+
+func TestLeaderboardMigratesScore(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	// The runs table as created before runs had a score
+	_, err = db.Exec(`
+		CREATE TABLE runs (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			player      TEXT    NOT NULL,
+			quiz_hash   TEXT    NOT NULL,
+			quiz_name   TEXT    NOT NULL,
+			correct     INTEGER NOT NULL,
+			total       INTEGER NOT NULL,
+			duration_ms INTEGER NOT NULL,
+			finished_at INTEGER NOT NULL
+		);
+		CREATE INDEX runs_by_quiz ON runs (quiz_hash, correct DESC, duration_ms);
+		INSERT INTO runs (player, quiz_hash, quiz_name, correct, total, duration_ms, finished_at)
+		VALUES ('raz', 'abc', 'test.csv', 6, 10, 1000, 1700000000);`)
+	if err != nil {
+		t.Fatalf("Failed to create the old tables: %v", err)
+	}
+
+	l, err := NewLeaderboard(db)
+	if err != nil {
+		t.Fatalf("NewLeaderboard returned error: %v", err)
+	}
+	if err := l.Record(Run{Player: "dana", QuizHash: "abc", QuizName: "test.csv", Score: 5.5, Correct: 7, Total: 10}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	top, err := l.Top("abc", 2)
+	if err != nil {
+		t.Fatalf("Top returned error: %v", err)
+	}
+	if len(top) != 2 || top[0].Player != "raz" || top[0].Score != 6 {
+		t.Errorf("Expected the old run to score its correct answers and rank first, but got %+v", top)
+	}
+	if _, err := NewLeaderboard(db); err != nil {
+		t.Errorf("Expected opening a migrated database to succeed, but got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package quiz
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells whether an Issue breaks a question bank or is merely suspicious.
+type Severity int
+
+const (
+	Warning Severity = iota // The bank loads, but the problem is likely a mistake
+	Error                   // The bank can't be used as is
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is a problem found in a question bank by Lint or Validate.
+type Issue struct {
+	Line     int // Line of the question bank, 0 if unknown
+	Severity Severity
+	Message  string
+}
+
+// String formats the issue as "line N: severity: message".
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Severity, i.Message)
+}
+
+// suspiciousSpaces lists whitespace that is hard to see or type inside a question or answer.
+var suspiciousSpaces = []struct {
+	r    rune
+	name string
+}{
+	{'\t', "a tab"},
+	{'\n', "a line break"},
+	{'\u00a0', "a non-breaking space"},
+	{'\u200b', "a zero-width space"},
+	{'\ufeff', "a byte order mark"},
+}
+
+// Validate checks parsed problems for empty questions or answers, duplicate
+// questions, conflicting answers for the same question and suspicious whitespace.
+// It reports every issue found, in the order of the problems.
+func Validate(problems []Problem) []Issue {
+	var issues []Issue
+	add := func(p Problem, severity Severity, format string, args ...interface{}) {
+		issues = append(issues, Issue{Line: p.Line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[string]Problem) // First problem per question
+	for i, p := range problems {
+		if p.Question == "" {
+			add(p, Error, "problem %d has an empty question", i+1)
+			continue
+		}
+		if p.Answer == "" {
+			add(p, Error, "empty answer for %q", p.Question)
+		}
+		for _, field := range []struct{ name, text string }{{"question", p.Question}, {"answer", p.Answer}} {
+			if strings.Contains(field.text, "  ") {
+				add(p, Warning, "repeated spaces in the %s %q", field.name, field.text)
+			}
+			for _, space := range suspiciousSpaces {
+				if strings.ContainsRune(field.text, space.r) {
+					add(p, Warning, "%s in the %s %q", space.name, field.name, field.text)
+				}
+			}
+		}
+
+		first, ok := seen[p.Question]
+		switch {
+		case !ok:
+			seen[p.Question] = p
+		case first.Answer != p.Answer:
+			add(p, Error, "conflicting answers for %q: %q here and %q %s", p.Question, p.Answer, first.Answer, where(first))
+		default:
+			add(p, Warning, "duplicate question %q, also %s", p.Question, where(first))
+		}
+	}
+	return issues
+}
+
+// where describes the location of p for issue messages.
+func where(p Problem) string {
+	if p.Line == 0 {
+		return "earlier"
+	}
+	return fmt.Sprintf("in line %d", p.Line)
+}
+
+// Lint checks the question bank data in the given format and reports every issue it
+// finds instead of stopping at the first. CSV files are checked line by line, GIFT
+// files report the questions they skip, and other formats report a parse error as a
+// single issue. It fails only for unknown formats.
+func Lint(data []byte, format string) ([]Issue, error) {
+	loader, err := LoaderFor(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		problems []Problem
+		issues   []Issue
+	)
+	switch loader.(type) {
+	case CSVLoader:
+		lines, starts, err := readCSV(bytes.NewReader(data))
+		if err != nil {
+			return []Issue{{Severity: Error, Message: err.Error()}}, nil
+		}
+		problems, issues = parseLines(lines, starts)
+	case GIFTLoader:
+		if problems, issues, err = parseGIFT(bytes.NewReader(data)); err != nil {
+			return []Issue{{Severity: Error, Message: err.Error()}}, nil
+		}
+	default:
+		if problems, err = loader.Load(bytes.NewReader(data)); err != nil {
+			return []Issue{{Severity: Error, Message: err.Error()}}, nil
+		}
+	}
+	if len(problems) == 0 && !hasErrors(issues) {
+		issues = append(issues, Issue{Severity: Error, Message: "no problems found"})
+	}
+	issues = append(issues, Validate(problems)...)
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// hasErrors reports whether any of the issues is an Error.
+func hasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	input := "5+5,10\n" +
+		"1+1\n" +
+		"What is the capital of France?, Paris\n" +
+		"5+5,10\n" +
+		"7+3,\n" +
+		"5+5,11\n" +
+		"2*2,4,9\n" +
+		"What is  the\u00a0answer?,42\n"
+	expected := []string{
+		"line 2: error: each line must have at least two columns",
+		"line 3: warning: leading or trailing whitespace in the answer \" Paris\"",
+		"line 4: warning: duplicate question \"5+5\", also in line 1",
+		"line 5: error: empty answer for \"7+3\"",
+		"line 6: error: conflicting answers for \"5+5\": \"11\" here and \"10\" in line 1",
+		"line 7: error: difficulty must be a number from 1 to 5, got \"9\"",
+		"line 8: warning: repeated spaces in the question \"What is  the\\u00a0answer?\"",
+		"line 8: warning: a non-breaking space in the question \"What is  the\\u00a0answer?\"",
+	}
+
+	issues, err := Lint([]byte(input), "csv")
+	if err != nil {
+		t.Fatalf("Lint returned error: %v", err)
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, but got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("Expected issue %q, but got %q", expected[i], issue)
+		}
+	}
+}
+
+func TestLintFormats(t *testing.T) {
+	issues, err := Lint([]byte("# Math\n\n- 5+5\n  Answer: 10\n- 5+5\n  Answer: 9\n"), "md")
+	if err != nil {
+		t.Fatalf("Lint returned error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Line != 5 || issues[0].Severity != Error {
+		t.Errorf("Expected a conflicting answer error in line 5, but got %v", issues)
+	}
+
+	issues, _ = Lint([]byte(`[{"question": "5+5"`), "json")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "failed to parse") {
+		t.Errorf("Expected a single parse error, but got %v", issues)
+	}
+
+	if _, err := Lint(nil, "xlsx"); err == nil {
+		t.Error("Expected an error for an unsupported format, but got none")
+	}
+}
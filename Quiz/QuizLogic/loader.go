@@ -0,0 +1,167 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Loader parses quiz problems from a question bank in a specific format.
+type Loader interface {
+	Load(r io.Reader) ([]Problem, error)
+}
+
+// CSVLoader loads problems from CSV in the format of 'question,answer'.
+type CSVLoader struct{}
+
+// JSONLoader loads problems from a JSON array of {"question": ..., "answer": ...} objects.
+type JSONLoader struct{}
+
+// YAMLLoader loads problems from a YAML list of question/answer mappings.
+type YAMLLoader struct{}
+
+// problemRecord represents a single problem in a structured question bank.
+// This struct is used to parse JSON / YAML question banks.
+type problemRecord struct {
+	Question   string   `yaml:"question" json:"question"`
+	Answer     string   `yaml:"answer" json:"answer"`
+	Difficulty int      `yaml:"difficulty" json:"difficulty"` // Optional, 0 if not rated
+	Tags       []string `yaml:"tags" json:"tags"`             // Optional categories
+	Hint       string   `yaml:"hint" json:"hint"`             // Optional hint
+	Weight     float64  `yaml:"weight" json:"weight"`         // Optional, 0 counts as 1
+}
+
+// LoaderFor returns the Loader for the given format name, as passed to the -format
+// flag or derived from a file extension by FormatFromPath.
+func LoaderFor(format string) (Loader, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return CSVLoader{}, nil
+	case "json":
+		return JSONLoader{}, nil
+	case "yaml", "yml":
+		return YAMLLoader{}, nil
+	case "md", "markdown":
+		return MarkdownLoader{}, nil
+	case "gift":
+		return GIFTLoader{}, nil
+	case "apkg", "anki":
+		return DefaultAnkiLoader, nil
+	default:
+		return nil, fmt.Errorf("unsupported quiz format: %q. Please use csv, json, yaml, md, gift or apkg", format)
+	}
+}
+
+// FormatFromPath derives the format name from the extension of a question bank file
+// or URL.
+func FormatFromPath(path string) string {
+	if IsURL(path) {
+		path = urlPath(path)
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// LoadOption configures how LoadFile parses a question bank.
+type LoadOption func(*loadOptions)
+
+// loadOptions holds the settings of a LoadFile call.
+type loadOptions struct {
+	anki AnkiLoader // Loader used for Anki decks
+}
+
+// WithAnkiFields picks the note fields of an Anki deck used as question and answer,
+// counting from 0. By default these are the fields of DefaultAnkiLoader.
+func WithAnkiFields(question, answer int) LoadOption {
+	return func(o *loadOptions) {
+		o.anki = AnkiLoader{QuestionField: question, AnswerField: answer}
+	}
+}
+
+// LoadFile reads and parses the question bank at path, a local file or an http(s)
+// URL (see ReadSource). An empty format is derived from the file extension. The raw
+// file contents are returned as well, e.g. for HashQuiz.
+func LoadFile(path, format string, opts ...LoadOption) ([]Problem, []byte, error) {
+	o := loadOptions{anki: DefaultAnkiLoader}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if format == "" {
+		format = FormatFromPath(path)
+	}
+	loader, err := LoaderFor(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := loader.(AnkiLoader); ok {
+		loader = o.anki
+	}
+	data, err := ReadSource(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	problems, err := loader.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	return problems, data, nil
+}
+
+// Load implements Loader for CSV question banks.
+func (CSVLoader) Load(r io.Reader) ([]Problem, error) {
+	return ParseCSV(r)
+}
+
+// Load implements Loader for JSON question banks.
+func (JSONLoader) Load(r io.Reader) ([]Problem, error) {
+	var records []problemRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to parse the provided JSON file: %v", err)
+	}
+	return buildProblems(records, "JSON")
+}
+
+// Load implements Loader for YAML question banks.
+func (YAMLLoader) Load(r io.Reader) ([]Problem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the provided YAML file: %v", err)
+	}
+	var records []problemRecord
+	if err := yaml.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse the provided YAML file: %v", err)
+	}
+	return buildProblems(records, "YAML")
+}
+
+// buildProblems converts parsed records into problems, trimming surrounding whitespace.
+func buildProblems(records []problemRecord, format string) ([]Problem, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no problems found in the provided %s file", format)
+	}
+	ret := make([]Problem, len(records))
+	for i, rec := range records {
+		if rec.Difficulty != 0 && (rec.Difficulty < MinDifficulty || rec.Difficulty > MaxDifficulty) {
+			return nil, fmt.Errorf("invalid difficulty %d for problem %d in the provided %s file: it must be from %d to %d",
+				rec.Difficulty, i+1, format, MinDifficulty, MaxDifficulty)
+		}
+		if rec.Weight < 0 || math.IsNaN(rec.Weight) || math.IsInf(rec.Weight, 0) {
+			return nil, fmt.Errorf("invalid weight %v for problem %d in the provided %s file: it must be a positive number",
+				rec.Weight, i+1, format)
+		}
+		ret[i] = Problem{
+			Question:   strings.TrimSpace(rec.Question),
+			Answer:     strings.TrimSpace(rec.Answer),
+			Difficulty: rec.Difficulty,
+			Tags:       ParseTags(strings.Join(rec.Tags, "|")),
+			Hint:       strings.TrimSpace(rec.Hint),
+			Weight:     rec.Weight,
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,102 @@
+package quiz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoaders(t *testing.T) {
+	expected := []Problem{
+		{Question: "5+5", Answer: "10", Tags: []string{"sample"}},
+		{Question: "What is the capital of France?", Answer: "Paris", Tags: []string{"sample"}},
+	}
+	tests := []struct {
+		format string
+		input  string
+	}{
+		{"csv", "5+5,10,,sample\nWhat is the capital of France?, Paris,,Sample\n"},
+		{"json", `[{"question": "5+5", "answer": "10", "tags": ["sample"]}, {"question": "What is the capital of France?", "answer": " Paris", "tags": ["Sample"]}]`},
+		{"yaml", "- question: 5+5\n  answer: 10\n  tags: [sample]\n- question: What is the capital of France?\n  answer: Paris\n  tags: [sample]\n"},
+		{"md", "# Sample\n\n1. 5+5\n   Answer: 10\n2. What is the capital\n   of France?\n   **Answer:** Paris\n"},
+		{"gift", "// Sample\n$CATEGORY: $course$/Sample\n\n::Sum:: 5+5 {#10}\n\nWhat is the capital of France? {=Paris ~London}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			loader, err := LoaderFor(tt.format)
+			if err != nil {
+				t.Fatalf("LoaderFor(%q) returned error: %v", tt.format, err)
+			}
+			problems, err := loader.Load(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			if len(problems) != len(expected) {
+				t.Fatalf("Expected %d problems, but got %d", len(expected), len(problems))
+			}
+			for i, problem := range problems {
+				problem.Line = 0 // Only some formats know lines
+				if !reflect.DeepEqual(problem, expected[i]) {
+					t.Errorf("Expected problem %v, but got %v", expected[i], problem)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadInvalidWeight(t *testing.T) {
+	loader, err := LoaderFor("yaml")
+	if err != nil {
+		t.Fatalf("LoaderFor returned error: %v", err)
+	}
+	for _, weight := range []string{"-1", ".nan", ".inf"} {
+		if _, err := loader.Load(strings.NewReader("- question: 5+5\n  answer: 10\n  weight: " + weight + "\n")); err == nil {
+			t.Errorf("Expected an error for the weight %s", weight)
+		}
+	}
+}
+
+func TestLoaderFor(t *testing.T) {
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"questions.csv", true},
+		{"questions.YML", true},
+		{"bank/questions.markdown", true},
+		{"questions.gift", true},
+		{"deck.apkg", true},
+		{"questions.xlsx", false},
+	}
+
+	for _, tt := range tests {
+		if _, err := LoaderFor(FormatFromPath(tt.path)); (err == nil) != tt.ok {
+			t.Errorf("LoaderFor(FormatFromPath(%q)) error = %v; want ok %v", tt.path, err, tt.ok)
+		}
+	}
+}
+
+func TestMarkdownLoaderMissingAnswer(t *testing.T) {
+	_, err := MarkdownLoader{}.Load(strings.NewReader("- 5+5\n- 1+1\n  Answer: 2\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected an error for the question in line 1, but got %v", err)
+	}
+}
+
+func TestMarkdownLoaderHint(t *testing.T) {
+	problems, err := MarkdownLoader{}.Load(strings.NewReader("- 5+5\n  Hint: Count your fingers\n  Answer: 10\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if problems[0].Hint != "Count your fingers" || problems[0].Answer != "10" {
+		t.Errorf("Expected the hint to be read, but got %+v", problems[0])
+	}
+}
+
+func TestMarkdownLoaderOrphanHint(t *testing.T) {
+	_, err := MarkdownLoader{}.Load(strings.NewReader("- 5+5\n  Answer: 10\n- Hint: Count your fingers\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an error for the hint in line 3, but got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// MarkdownLoader loads problems from a Markdown question list. Each list item is a
+// question, and the answer follows on its own line prefixed with "Answer:". An
+// optional "Hint:" line before the answer holds the hint of the question.
+// Headings tag the questions below them with their text as category, and other
+// text between questions is ignored.
+//
+// Example:
+//
+//	# Arithmetic
+//
+//	1. 5+5
+//	   Hint: Count your fingers
+//	   Answer: 10
+//	- What is the capital of France?
+//	  **Answer:** Paris
+type MarkdownLoader struct{}
+
+var (
+	// mdListItem matches a bulleted or numbered list item and captures its text.
+	mdListItem = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+	// mdAnswer matches an answer line, optionally in bold, a list item or a quote.
+	mdAnswer = regexp.MustCompile(`^(?:(?:[-*+]|>)\s*)?(?:\*\*|__)?[Aa]nswer:(?:\*\*|__)?\s*(.*)$`)
+	// mdHint matches a hint line, formatted like an answer line.
+	mdHint = regexp.MustCompile(`^(?:(?:[-*+]|>)\s*)?(?:\*\*|__)?[Hh]int:(?:\*\*|__)?\s*(.*)$`)
+)
+
+// Load implements Loader for Markdown question banks.
+func (MarkdownLoader) Load(r io.Reader) ([]Problem, error) {
+	var (
+		ret      []Problem
+		current  *Problem // Question waiting for its answer
+		start    int      // Line number of the current question
+		category []string // Tags from the closest heading
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// An answer completes the question it belongs to
+		if m := mdAnswer.FindStringSubmatch(line); m != nil {
+			if current == nil {
+				return nil, fmt.Errorf("invalid Markdown format in line %d: answer without a question", n)
+			}
+			current.Answer = strings.TrimSpace(m[1])
+			ret = append(ret, *current)
+			current = nil
+			continue
+		}
+
+		if m := mdHint.FindStringSubmatch(line); m != nil {
+			if current == nil {
+				return nil, fmt.Errorf("invalid Markdown format in line %d: hint without a question", n)
+			}
+			current.Hint = strings.TrimSpace(m[1])
+			continue
+		}
+
+		if m := mdListItem.FindStringSubmatch(line); m != nil {
+			if current != nil {
+				return nil, fmt.Errorf("invalid Markdown format in line %d: question has no answer", start)
+			}
+			current, start = &Problem{Question: strings.TrimSpace(m[1]), Tags: category, Line: n}, n
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			category = ParseTags(strings.TrimLeft(line, "# "))
+			continue
+		}
+
+		// Any other line either continues the current question or is ignored
+		if current != nil {
+			current.Question += " " + line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the provided Markdown file: %v", err)
+	}
+	if current != nil {
+		return nil, fmt.Errorf("invalid Markdown format in line %d: question has no answer", start)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no problems found in the provided Markdown file")
+	}
+	return ret, nil
+}
@@ -0,0 +1,136 @@
+package quiz
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultTypoRate is the typo tolerance of FuzzyMatcher: one typo per five characters.
+const DefaultTypoRate = 0.2
+
+// Matcher decides whether an answer matches the expected one. The zero Matcher
+// requires an exact match apart from surrounding whitespace.
+type Matcher struct {
+	// Normalize ignores case, diacritics, punctuation and repeated whitespace, after
+	// Unicode NFC normalization, so "Zurich" matches "Zürich".
+	Normalize bool
+	// TypoRate is the Levenshtein distance tolerated per character of the expected
+	// answer, rounded down: with 0.2 a five letter word may have one typo. Typos are
+	// never tolerated in the digits of an answer, so "Apollo 12" is wrong for
+	// "Apollo 11". 0 disables it.
+	TypoRate float64
+	// Expressions accepts any arithmetic expression that evaluates to a numeric
+	// expected answer, so "10.0", "2*5" and "1e1" are all right for 10.
+	Expressions bool
+}
+
+// Predefined matchers for the -match modes.
+var (
+	ExactMatcher      = Matcher{}
+	NormalizedMatcher = Matcher{Normalize: true}
+	FuzzyMatcher      = Matcher{Normalize: true, TypoRate: DefaultTypoRate}
+)
+
+// MatcherFor returns the matcher of a match mode: "exact", "normalized" or "fuzzy".
+func MatcherFor(mode string) (Matcher, error) {
+	switch strings.ToLower(mode) {
+	case "exact", "":
+		return ExactMatcher, nil
+	case "normalized":
+		return NormalizedMatcher, nil
+	case "fuzzy":
+		return FuzzyMatcher, nil
+	default:
+		return Matcher{}, fmt.Errorf("unsupported match mode: %q. Please use exact, normalized or fuzzy", mode)
+	}
+}
+
+// Match reports whether answer matches want. Numbers are never normalized or
+// corrected for typos, since a sign or a digit off is a wrong answer rather than a
+// typo; they are compared by value with Expressions and exactly otherwise. An answer
+// that normalization leaves empty, such as "?", only matches exactly.
+func (m Matcher) Match(answer, want string) bool {
+	if checkAnswer(answer, want) {
+		return true
+	}
+	if isNumber(want) {
+		return m.Expressions && sameValue(answer, want)
+	}
+	answer, want = strings.TrimSpace(answer), strings.TrimSpace(want)
+	if m.Normalize {
+		answer, want = normalizeAnswer(answer), normalizeAnswer(want)
+		if answer == "" {
+			return false // Exact matches were accepted above
+		}
+		if answer == want {
+			return true
+		}
+	}
+	if m.TypoRate <= 0 || !slices.Equal(digitsRe.FindAllString(answer, -1), digitsRe.FindAllString(want, -1)) {
+		return false
+	}
+	wanted := []rune(want)
+	return levenshtein([]rune(answer), wanted) <= int(m.TypoRate*float64(len(wanted)))
+}
+
+// numberRe matches a decimal number such as "10", "-2.5", ".5" or "1e3". Unlike
+// strconv.ParseFloat it rejects words such as "NaN" and "Inf" and hexadecimal numbers.
+var numberRe = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// digitsRe matches a run of digits, such as the year in "In 1945".
+var digitsRe = regexp.MustCompile(`[0-9]+`)
+
+// isNumber reports whether s is a decimal number.
+func isNumber(s string) bool {
+	return numberRe.MatchString(strings.TrimSpace(s))
+}
+
+// answerFolder removes diacritics and folds case. Decomposing first separates the
+// accents from their letters, and recomposing afterwards yields NFC.
+var answerFolder = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), cases.Fold(), norm.NFC)
+
+// normalizeAnswer folds s for comparison: no case, diacritics or punctuation, and
+// single spaces between words.
+func normalizeAnswer(s string) string {
+	folded, _, err := transform.String(answerFolder, s)
+	if err != nil {
+		folded = s
+	}
+	folded = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, folded)
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// levenshtein returns the number of single character insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
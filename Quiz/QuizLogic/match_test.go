@@ -0,0 +1,85 @@
+package quiz
+
+import "testing"
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		m      Matcher
+		answer string
+		want   string
+		ok     bool
+	}{
+		{ExactMatcher, " Paris ", "Paris", true},
+		{ExactMatcher, "paris", "Paris", false},
+		{NormalizedMatcher, "paris", "Paris", true},
+		{NormalizedMatcher, "zurich", "Zürich", true},
+		{NormalizedMatcher, "Zürich", "Zürich", true}, // Decomposed and composed umlaut
+		{NormalizedMatcher, "STRASSE", "straße", true},
+		{NormalizedMatcher, "dont  stop", "Don't stop!", true},
+		{NormalizedMatcher, "pariss", "Paris", false},
+		{FuzzyMatcher, "pariss", "Paris", true},
+		{FuzzyMatcher, "Barcelna", "Barcelona", true},
+		{FuzzyMatcher, "Barcelnoa", "Barcelona", false}, // A swap is two typos, one allowed in nine letters
+		{FuzzyMatcher, "Berlin", "Paris", false},
+		{FuzzyMatcher, "ox", "ax", false}, // Too short for a typo
+		{FuzzyMatcher, "-5", "5", false},  // Numbers have to match exactly
+		{FuzzyMatcher, "1235", "1234", false},
+		{Matcher{TypoRate: 0.5}, "Paros", "Paris", true},
+		{NormalizedMatcher, "NAN", "nan", true}, // Words, not numbers
+		{NormalizedMatcher, "Infinity", "infinity", true},
+		{Matcher{Expressions: true}, "inf", "Inf", false},
+		{FuzzyMatcher, "Apollo 12", "Apollo 11", false}, // Digits in words have to match too
+		{FuzzyMatcher, "In 1946", "In 1945", false},
+		{FuzzyMatcher, "Apolo 11", "Apollo 11", true},
+		{Matcher{TypoRate: 0.5}, "Apollo 111", "Apollo 11", false},
+		{NormalizedMatcher, " ", "?", false}, // Nothing left after normalization
+		{NormalizedMatcher, "!", "?", false},
+		{NormalizedMatcher, "?", "?", true},
+		{FuzzyMatcher, "", "?!", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.Match(tt.answer, tt.want); got != tt.ok {
+			t.Errorf("%+v.Match(%q, %q) = %v; want %v", tt.m, tt.answer, tt.want, got, tt.ok)
+		}
+	}
+}
+
+func TestIsNumber(t *testing.T) {
+	for _, s := range []string{"10", " -2.5 ", "+3", ".5", "5.", "1e3", "2.5E-2"} {
+		if !isNumber(s) {
+			t.Errorf("Expected %q to be a number", s)
+		}
+	}
+	for _, s := range []string{"", "NaN", "nan", "Inf", "-inf", "Infinity", "0x10", "1_000", "1e", "."} {
+		if isNumber(s) {
+			t.Errorf("Expected %q not to be a number", s)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherFor(t *testing.T) {
+	if m, err := MatcherFor("Fuzzy"); err != nil || m != FuzzyMatcher {
+		t.Errorf("MatcherFor(%q) = %+v, %v", "Fuzzy", m, err)
+	}
+	if _, err := MatcherFor("loose"); err == nil {
+		t.Error("Expected an error for an unknown match mode")
+	}
+}
@@ -0,0 +1,138 @@
+package quiz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Keys of the messages written during a quiz. Each key is also the English format
+// string, used as is unless the catalog has an entry for it.
+const (
+	msgProblem       = "Problem #%d: %s = "
+	msgTimeUp        = "\nTime's up!\n"
+	msgNoHint        = "There is no hint for this problem.\n"
+	msgHint          = "Hint: %s\n"
+	msgSummary       = "You answered %d questions correctly and got %d wrong.\n"
+	msgSkill         = "Estimated skill level: %.1f of %d\n"
+	msgScore         = "Your score: %g of %g points.\n"
+	msgPausedLeft    = "Paused with %s left. "
+	msgPaused        = "Paused. "
+	msgPauseHelp     = "Type %s to continue or %s to save the session and quit.\n"
+	msgSaveFailed    = "Failed to save the session: %v\n"
+	msgSaved         = "Session saved to %s.\n"
+	msgHurry         = "\nHurry up, %s left!\n"
+	msgTurn          = "\n== %s's turn (%d of %d) ==\nPress Enter when ready."
+	msgScoreboard    = "\n== Scoreboard ==\n"
+	msgStanding      = "  %d. %s - %d/%d correct, %g of %g points, %s\n"
+	msgCategoryTitle = "Score by category:\n"
+)
+
+// translations holds the messages of every language but English, by key.
+var translations = map[language.Tag]map[string]catalog.Message{
+	language.Spanish: {
+		msgProblem: catalog.String("Problema #%d: %s = "),
+		msgTimeUp:  catalog.String("\n¡Se acabó el tiempo!\n"),
+		msgNoHint:  catalog.String("No hay ninguna pista para este problema.\n"),
+		msgHint:    catalog.String("Pista: %s\n"),
+		msgSummary: plural.Selectf(1, "%d",
+			"one", "Respondiste %[1]d pregunta correctamente y fallaste %[2]d.\n",
+			"other", "Respondiste %[1]d preguntas correctamente y fallaste %[2]d.\n"),
+		msgSkill: catalog.String("Nivel de habilidad estimado: %.1f de %d\n"),
+		msgScore: plural.Selectf(2, "%g",
+			"one", "Tu puntuación: %[1]g de %[2]g punto.\n",
+			"other", "Tu puntuación: %[1]g de %[2]g puntos.\n"),
+		msgPausedLeft:    catalog.String("En pausa, quedan %s. "),
+		msgPaused:        catalog.String("En pausa. "),
+		msgPauseHelp:     catalog.String("Escribe %s para continuar o %s para guardar la sesión y salir.\n"),
+		msgSaveFailed:    catalog.String("No se pudo guardar la sesión: %v\n"),
+		msgSaved:         catalog.String("Sesión guardada en %s.\n"),
+		msgHurry:         catalog.String("\n¡Date prisa, quedan %s!\n"),
+		msgTurn:          catalog.String("\n== Turno de %s (%d de %d) ==\nPulsa Intro cuando estés listo."),
+		msgScoreboard:    catalog.String("\n== Clasificación ==\n"),
+		msgStanding:      catalog.String("  %d. %s - %d/%d correctas, %g de %g puntos, %s\n"),
+		msgCategoryTitle: catalog.String("Puntuación por categoría:\n"),
+	},
+}
+
+// englishPlurals holds the English messages that depend on a count.
+var englishPlurals = map[string]catalog.Message{
+	msgSummary: plural.Selectf(1, "%d",
+		"one", "You answered %[1]d question correctly and got %[2]d wrong.\n",
+		"other", "You answered %[1]d questions correctly and got %[2]d wrong.\n"),
+	msgScore: plural.Selectf(2, "%g",
+		"one", "Your score: %[1]g of %[2]g point.\n",
+		"other", "Your score: %[1]g of %[2]g points.\n"),
+}
+
+var (
+	// messages is the catalog of quiz messages in every supported language.
+	messages = buildCatalog()
+	// supportedLanguages lists the languages of the catalog, English first as the default.
+	supportedLanguages = append([]language.Tag{language.English}, tagsOf(translations)...)
+	// languageMatcher picks the closest supported language.
+	languageMatcher = language.NewMatcher(supportedLanguages)
+)
+
+// buildCatalog builds the message catalog from the translations.
+func buildCatalog() *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for key, msg := range englishPlurals {
+		b.Set(language.English, key, msg)
+	}
+	for tag, msgs := range translations {
+		for key, msg := range msgs {
+			b.Set(tag, key, msg)
+		}
+	}
+	return b
+}
+
+// tagsOf returns the languages of translations, sorted by code.
+func tagsOf(translations map[language.Tag]map[string]catalog.Message) []language.Tag {
+	var tags []language.Tag
+	for tag := range translations {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].String() < tags[j].String()
+	})
+	return tags
+}
+
+// Languages returns the codes of the languages quiz messages are available in.
+func Languages() []string {
+	var codes []string
+	for _, tag := range supportedLanguages {
+		codes = append(codes, tag.String())
+	}
+	return codes
+}
+
+// ParseLanguage returns the supported language closest to code, a BCP 47 tag such as
+// "es" or "es-MX". POSIX locales such as "es_ES.UTF-8" are accepted too. It fails if
+// no supported language is close.
+func ParseLanguage(code string) (language.Tag, error) {
+	if i := strings.IndexAny(code, ".@"); i >= 0 {
+		code = code[:i]
+	}
+	tag, err := language.Parse(strings.ReplaceAll(code, "_", "-"))
+	if err != nil {
+		return language.English, fmt.Errorf("invalid language %q: %v", code, err)
+	}
+	_, i, confidence := languageMatcher.Match(tag)
+	if confidence == language.No {
+		return language.English, fmt.Errorf("unsupported language: %q. Please use one of %s", code, strings.Join(Languages(), ", "))
+	}
+	return supportedLanguages[i], nil
+}
+
+// newPrinter returns a printer for the quiz messages in the supported language tag.
+func newPrinter(tag language.Tag) *message.Printer {
+	return message.NewPrinter(tag, message.Catalog(messages))
+}
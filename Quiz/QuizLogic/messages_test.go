@@ -0,0 +1,82 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestTranslationsComplete(t *testing.T) {
+	keys := []string{
+		msgProblem, msgTimeUp, msgNoHint, msgHint, msgSummary, msgSkill, msgScore, msgPausedLeft, msgPaused,
+		msgPauseHelp, msgSaveFailed, msgSaved, msgHurry, msgTurn, msgScoreboard, msgStanding, msgCategoryTitle,
+	}
+	for tag, msgs := range translations {
+		for _, key := range keys {
+			if _, ok := msgs[key]; !ok {
+				t.Errorf("Missing %s translation of %q", tag, key)
+			}
+		}
+		if len(msgs) != len(keys) {
+			t.Errorf("Expected %d %s translations, but got %d", len(keys), tag, len(msgs))
+		}
+	}
+}
+
+func TestSessionLanguage(t *testing.T) {
+	tests := []struct {
+		tag   language.Tag
+		input string
+		want  []string
+	}{
+		{language.English, "10\n", []string{"Problem #1: 5+5 = ", "You answered 1 question correctly and got 2 wrong."}},
+		{language.English, "10\n2\n", []string{"You answered 2 questions correctly and got 1 wrong."}},
+		{language.Spanish, "10\n", []string{"Problema #1: 5+5 = ", "Respondiste 1 pregunta correctamente y fallaste 2."}},
+		{language.Spanish, "10\n2\n10\n", []string{"Respondiste 3 preguntas correctamente y fallaste 0."}},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		s := NewSession(sessionProblems, WithInput(strings.NewReader(tt.input)), WithOutput(&out), WithLanguage(tt.tag))
+		s.Run(context.Background())
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected %s output to contain %q, but got %q", tt.tag, want, out.String())
+			}
+		}
+	}
+}
+
+func TestSessionScorePlural(t *testing.T) {
+	var out bytes.Buffer
+	problems := []Problem{{Question: "5+5", Answer: "10", Hint: "Count"}}
+	s := NewSession(problems, WithInput(strings.NewReader("?\n10\n")), WithOutput(&out), WithLanguage(language.Spanish))
+	s.Run(context.Background())
+	if want := "Tu puntuación: 0,5 de 1 punto."; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		code string
+		want language.Tag
+		ok   bool
+	}{
+		{"en", language.English, true},
+		{"es", language.Spanish, true},
+		{"es-MX", language.Spanish, true},
+		{"es_ES.UTF-8", language.Spanish, true},
+		{"en_US", language.English, true},
+		{"fr", language.English, false},
+		{"not a language", language.English, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLanguage(tt.code)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseLanguage(%q) = %v, %v; want %v, ok %v", tt.code, got, err, tt.want, tt.ok)
+		}
+	}
+}
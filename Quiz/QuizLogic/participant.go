@@ -0,0 +1,140 @@
+package quiz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Participant is a player's connection to a multiplayer quiz room.
+type Participant struct {
+	conn  *websocket.Conn
+	Total int // Number of questions in the room
+}
+
+// JoinRoom connects to the room with the given code on a quiz server, e.g.
+// "ws://localhost:8080", as player name.
+func JoinRoom(ctx context.Context, server, code, name string) (*Participant, error) {
+	u, err := url.Parse(strings.TrimSuffix(server, "/") + "/join")
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address %q: %v", server, err)
+	}
+	u.RawQuery = url.Values{"code": {code}, "name": {name}}.Encode()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", server, err)
+	}
+	p := &Participant{conn: conn}
+
+	welcome, err := p.Next()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if welcome.Type != MsgWelcome {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected %q message from the server", welcome.Type)
+	}
+	p.Total = welcome.Total
+	return p, nil
+}
+
+// Next waits for the next message from the room. Error messages are returned as errors.
+func (p *Participant) Next() (Message, error) {
+	var msg Message
+	if err := p.conn.ReadJSON(&msg); err != nil {
+		return Message{}, fmt.Errorf("connection to the room lost: %v", err)
+	}
+	if msg.Type == MsgError {
+		return msg, fmt.Errorf("room error: %s", msg.Error)
+	}
+	return msg, nil
+}
+
+// Answer submits an answer to the question with the given 1-based index.
+func (p *Participant) Answer(index int, answer string) error {
+	return p.conn.WriteJSON(Message{Type: MsgAnswer, Index: index, Answer: answer})
+}
+
+// Close disconnects from the room.
+func (p *Participant) Close() error {
+	return p.conn.Close()
+}
+
+// Play takes part in the room until it ends, printing questions and scoreboards to
+// out and sending each line read from in as the answer to the open question.
+// It returns the final scores.
+func (p *Participant) Play(ctx context.Context, in io.Reader, out io.Writer) ([]Score, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Read messages in the background so answers can be typed while waiting
+	type incoming struct {
+		msg Message
+		err error
+	}
+	messages := make(chan incoming)
+	go func() {
+		for {
+			msg, err := p.Next()
+			select {
+			case messages <- incoming{msg, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	fmt.Fprintf(out, "Joined! Waiting for the host to start (%d questions)...\n", p.Total)
+	answers := readAnswers(ctx, in)
+	open := 0 // Index of the question waiting for an answer, 0 if none
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case answer, ok := <-answers:
+			if !ok {
+				answers = nil // No more input, keep watching the room
+				continue
+			}
+			if open == 0 {
+				fmt.Fprintln(out, "No question is open right now.")
+				continue
+			}
+			if err := p.Answer(open, answer); err != nil {
+				return nil, err
+			}
+			open = 0
+		case m := <-messages:
+			if m.err != nil {
+				return nil, m.err
+			}
+			switch msg := m.msg; msg.Type {
+			case MsgQuestion:
+				open = msg.Index
+				fmt.Fprintf(out, "\nProblem #%d of %d (%ds): %s = ", msg.Index, msg.Total, msg.Seconds, msg.Question)
+			case MsgResult:
+				open = 0
+				if msg.Correct {
+					fmt.Fprintf(out, "\nCorrect! +%d points\n", msg.Points)
+				} else {
+					fmt.Fprintf(out, "\nWrong. The answer was %s\n", msg.Answer)
+				}
+			case MsgScoreboard:
+				printScores(out, msg.Scores)
+			case MsgEnd:
+				fmt.Fprintln(out, "\nFinal scores:")
+				printScores(out, msg.Scores)
+				return msg.Scores, nil
+			}
+		}
+	}
+}
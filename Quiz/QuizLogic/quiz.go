@@ -1,12 +1,12 @@
 package quiz
 
 import (
-	"bufio"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,39 +14,135 @@ import (
 // Define the minimum number of columns expected in the CSV file.
 const MinColumns = 2
 
-// Represent a quiz question and its corresponding answer.
-type problem struct {
-	q string
-	a string
+// Define the range of difficulty ratings a problem can carry.
+const (
+	MinDifficulty     = 1
+	MaxDifficulty     = 5
+	DefaultDifficulty = 3 // Assumed for problems without a rating
+)
+
+// Problem represents a quiz question and its corresponding answer.
+type Problem struct {
+	Question   string
+	Answer     string
+	Difficulty int      // From MinDifficulty to MaxDifficulty, 0 if not rated
+	Tags       []string // Normalized categories of the problem
+	Hint       string   // Revealed on request, empty if there is none
+	Weight     float64  // Points for a correct answer, 0 counts as 1
+	Line       int      // Line of the question bank the problem starts at, 0 if unknown
 }
 
-// ParseCSV reads a CSV file and converts it into a slice of problems.
-func ParseCSV(file io.Reader) ([]problem, error) {
-	r := csv.NewReader(file)
-	lines, err := r.ReadAll()
+// ParseCSV reads a CSV file and converts it into a slice of problems. It fails on
+// the first malformed line; Lint reports every issue of a question bank instead.
+func ParseCSV(file io.Reader) ([]Problem, error) {
+	lines, starts, err := readCSV(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse the provided CSV file: %v", err)
+		return nil, err
 	}
 	if len(lines) == 0 {
 		return nil, fmt.Errorf("no problems found in the provided CSV file")
 	}
-	return parseLines(lines), nil
+	problems, issues := parseLines(lines, starts)
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return nil, fmt.Errorf("invalid CSV format in line %d: %s", issue.Line, issue.Message)
+		}
+	}
+	return problems, nil
+}
+
+// readCSV reads every record of a CSV file along with the line each record starts at.
+func readCSV(file io.Reader) ([][]string, []int, error) {
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1 // Optional columns may be left out per line
+	var (
+		lines  [][]string
+		starts []int
+	)
+	for {
+		line, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse the provided CSV file: %v", err)
+		}
+		start, _ := r.FieldPos(0)
+		lines, starts = append(lines, line), append(starts, start)
+	}
+	return lines, starts, nil
 }
 
-// parseLines processes CSV lines and returns a slice of problem structs.
-func parseLines(lines [][]string) []problem {
-	ret := make([]problem, len(lines))
+// parseLines processes CSV lines and returns a slice of problem structs, along with
+// the issues found in the lines. Optional further columns hold the difficulty rating
+// of the problem, its tags separated by '|', a hint and the weight of the problem, in
+// that order. starts holds the line each record starts at; if nil, records are
+// numbered from 1. Lines with too few columns are skipped.
+func parseLines(lines [][]string, starts []int) ([]Problem, []Issue) {
+	var (
+		ret    []Problem
+		issues []Issue
+	)
 	for i, line := range lines {
+		n := i + 1
+		if starts != nil {
+			n = starts[i]
+		}
 		if len(line) < MinColumns {
-			log.Fatalf("Invalid CSV format in line %d: each line must have at least two columns.", i+1)
+			issues = append(issues, Issue{Line: n, Severity: Error, Message: "each line must have at least two columns"})
+			continue
+		}
+		p := Problem{
+			Question: strings.TrimSpace(line[0]),
+			Answer:   strings.TrimSpace(line[1]),
+			Line:     n,
+		}
+		for col, name := range []string{"question", "answer"} {
+			if line[col] != "" && line[col] != strings.TrimSpace(line[col]) {
+				issues = append(issues, Issue{Line: n, Severity: Warning, Message: fmt.Sprintf("leading or trailing whitespace in the %s %q", name, line[col])})
+			}
+		}
+		if len(line) > MinColumns && strings.TrimSpace(line[2]) != "" {
+			d, err := parseDifficulty(line[2])
+			if err != nil {
+				issues = append(issues, Issue{Line: n, Severity: Error, Message: err.Error()})
+			}
+			p.Difficulty = d
+		}
+		if len(line) > MinColumns+1 {
+			p.Tags = ParseTags(line[3])
 		}
-		ret[i] = problem{
-			q: strings.TrimSpace(line[0]),
-			a: strings.TrimSpace(line[1]),
+		if len(line) > MinColumns+2 {
+			p.Hint = strings.TrimSpace(line[4])
 		}
+		if len(line) > MinColumns+3 && strings.TrimSpace(line[5]) != "" {
+			w, err := parseWeight(line[5])
+			if err != nil {
+				issues = append(issues, Issue{Line: n, Severity: Error, Message: err.Error()})
+			}
+			p.Weight = w
+		}
+		ret = append(ret, p)
+	}
+	return ret, issues
+}
+
+// parseDifficulty parses a difficulty rating between MinDifficulty and MaxDifficulty.
+func parseDifficulty(s string) (int, error) {
+	d, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || d < MinDifficulty || d > MaxDifficulty {
+		return 0, fmt.Errorf("difficulty must be a number from %d to %d, got %q", MinDifficulty, MaxDifficulty, s)
 	}
-	return ret
+	return d, nil
+}
 
+// parseWeight parses the positive, finite weight of a problem.
+func parseWeight(s string) (float64, error) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		return 0, fmt.Errorf("weight must be a positive number, got %q", s)
+	}
+	return w, nil
 }
 
 // checkAnswer verifies if the provided answer matches the correct answer.
@@ -54,27 +150,12 @@ func checkAnswer(a string, q string) bool {
 	return strings.TrimSpace(a) == strings.TrimSpace(q)
 }
 
-// RunQuiz runs the quiz by asking questions and checking answers against a timer.
-func RunQuiz(timer <-chan time.Time, problems []problem) {
-	correct := 0
-	scanner := bufio.NewScanner(os.Stdin)
-	for i, p := range problems { // Iterate through the questions
-		fmt.Printf("Problem #%d: %s = ", i+1, p.q)
-		answerCh := make(chan string)
-		go func() {
-			scanner.Scan()
-			answerCh <- scanner.Text()
-		}() // Capture user input asynchronously
-		select {
-		case <-timer: // Time's up
-			fmt.Println("\nTime's up!")
-			fmt.Printf("You answered %d questions correctly and got %d wrong.\n", correct, len(problems)-correct)
-			return
-		case answer := <-answerCh: // Check the user's answer
-			if checkAnswer(answer, p.a) {
-				correct++
-			}
-		}
-	}
-	fmt.Printf("You answered %d question correctly and got %d wrong.\n", correct, len(problems)-correct)
+// RunQuiz runs the quiz on standard input and output, asking questions and checking
+// answers until all problems are answered or the timer fires, and returns the result
+// with the outcome of every question asked. It is a thin wrapper around Session for
+// callers that manage their own timer; opts configure the session, e.g. its scoring policy.
+func RunQuiz(timer <-chan time.Time, problems []Problem, opts ...SessionOption) Result {
+	s := NewSession(problems, opts...)
+	s.expired = timer
+	return s.Run(context.Background())
 }
@@ -1,6 +1,7 @@
 package quiz
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -9,23 +10,44 @@ func TestParseLines(t *testing.T) {
 		{"question1", " answer1 "},
 		{"question2 ", "answer2"},
 	}
-	expected := []problem{
-		{q: "question1", a: "answer1"},
-		{q: "question2", a: "answer2"},
+	expected := []Problem{
+		{Question: "question1", Answer: "answer1"},
+		{Question: "question2", Answer: "answer2"},
 	}
 
-	problems := parseLines(lines)
+	problems, issues := parseLines(lines, nil)
+	if len(issues) != 2 {
+		t.Errorf("Expected a whitespace warning per line, but got %v", issues)
+	}
 	if len(problems) != len(expected) {
 		t.Fatalf("Expected %d problems, but got %d", len(expected), len(problems))
 	}
 
 	for i, problem := range problems {
-		if problem.q != expected[i].q || problem.a != expected[i].a {
+		if problem.Question != expected[i].Question || problem.Answer != expected[i].Answer {
 			t.Errorf("Expected problem %v, but got %v", expected[i], problem)
 		}
 	}
 }
 
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  string
+	}{
+		{"5+5,10\n1+1\n", "line 2"},
+		{"5+5,10\n\"multi\nline\",2\n7+3,10,9\n", "line 4"},
+		{"5+5,10,,,,heavy\n", "line 1"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCSV(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.line) {
+			t.Errorf("ParseCSV(%q) error = %v; want an error in %s", tt.input, err, tt.line)
+		}
+	}
+}
+
 func TestCheckAnswer(t *testing.T) {
 	tests := []struct {
 		answer   string
@@ -44,3 +66,39 @@ func TestCheckAnswer(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCSVDifficulty(t *testing.T) {
+	problems, err := ParseCSV(strings.NewReader("5+5,10,1\n12*12,144, 4 \n7+3,10,\n"))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+
+	expected := []int{1, 4, 0}
+	for i, problem := range problems {
+		if problem.Difficulty != expected[i] {
+			t.Errorf("Expected difficulty %d for %q, but got %d", expected[i], problem.Question, problem.Difficulty)
+		}
+	}
+}
+
+func TestParseCSVInvalidWeight(t *testing.T) {
+	for _, weight := range []string{"0", "-1", "two", "NaN", "Inf", "+Infinity"} {
+		if _, err := ParseCSV(strings.NewReader("5+5,10,,,," + weight + "\n")); err == nil {
+			t.Errorf("Expected an error for the weight %q", weight)
+		}
+	}
+}
+
+func TestParseCSVHintAndWeight(t *testing.T) {
+	problems, err := ParseCSV(strings.NewReader("5+5,10,,, Count your fingers ,2.5\n7+3,10\n"))
+	if err != nil {
+		t.Fatalf("ParseCSV returned error: %v", err)
+	}
+
+	if problems[0].Hint != "Count your fingers" || problems[0].Weight != 2.5 {
+		t.Errorf("Expected hint and weight 2.5 for %q, but got %+v", problems[0].Question, problems[0])
+	}
+	if problems[1].Hint != "" || weightOf(problems[1]) != 1 {
+		t.Errorf("Expected no hint and weight 1 for %q, but got %+v", problems[1].Question, problems[1])
+	}
+}
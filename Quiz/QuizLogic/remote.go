@@ -0,0 +1,192 @@
+package quiz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds a whole question bank download, so an unreachable server
+// falls back to the cached copy quickly.
+const fetchTimeout = 10 * time.Second
+
+// Fetcher downloads question banks over HTTP and keeps a copy of each in a cache
+// directory. Later fetches are conditional on the cached copy's ETag or Last-Modified
+// date, and the cached copy is used when the server can't be reached.
+type Fetcher struct {
+	Client   *http.Client
+	CacheDir string    // Where fetched question banks are cached, no caching if empty
+	Warnings io.Writer // Where fallbacks to the cached copy are reported, if not nil
+}
+
+// cacheMeta holds the validators of a cached question bank.
+type cacheMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// NewFetcher creates a Fetcher caching in DefaultCacheDir and reporting fallbacks to
+// os.Stderr.
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		Client:   &http.Client{Timeout: fetchTimeout},
+		CacheDir: DefaultCacheDir(),
+		Warnings: os.Stderr,
+	}
+}
+
+// DefaultCacheDir returns the directory fetched question banks are cached in: quiz in
+// the user's cache directory, or in the temporary directory if there is none.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "quiz")
+}
+
+// IsURL reports whether path is an http or https URL rather than a local file.
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// ReadSource reads the question bank at path, which is a local file or an http(s)
+// URL fetched with NewFetcher.
+func ReadSource(path string) ([]byte, error) {
+	if IsURL(path) {
+		return NewFetcher().Fetch(context.Background(), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the question bank file %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// Fetch downloads the question bank at rawURL. If the server reports that the cached
+// copy is still current, or can't be reached or fails with a server error, the cached
+// copy is returned instead; it is an error only if there is none.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	cached, meta := f.readCache(rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question bank URL %s: %v", rawURL, err)
+	}
+	if cached != nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return f.fallback(rawURL, cached, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached, nil
+	case resp.StatusCode == http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return f.fallback(rawURL, cached, err)
+		}
+		meta := cacheMeta{URL: rawURL, ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		if err := f.writeCache(rawURL, data, meta); err != nil && f.Warnings != nil {
+			fmt.Fprintf(f.Warnings, "Failed to cache %s: %v\n", rawURL, err)
+		}
+		return data, nil
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return f.fallback(rawURL, cached, fmt.Errorf("server responded %s", resp.Status))
+	default:
+		return nil, fmt.Errorf("failed to fetch the question bank %s: server responded %s", rawURL, resp.Status)
+	}
+}
+
+// fallback returns the cached copy of rawURL after a failed fetch, or the error if
+// there is none.
+func (f *Fetcher) fallback(rawURL string, cached []byte, err error) ([]byte, error) {
+	if cached == nil {
+		return nil, fmt.Errorf("failed to fetch the question bank %s: %v", rawURL, err)
+	}
+	if f.Warnings != nil {
+		fmt.Fprintf(f.Warnings, "Couldn't fetch %s (%v), using the cached copy.\n", rawURL, err)
+	}
+	return cached, nil
+}
+
+// cachePath returns the path of the cached copy of rawURL without extension. The
+// metadata is stored next to it.
+func (f *Fetcher) cachePath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(f.CacheDir, hex.EncodeToString(sum[:8]))
+}
+
+// readCache returns the cached copy of rawURL and its metadata, or nil if it isn't
+// cached.
+func (f *Fetcher) readCache(rawURL string) ([]byte, cacheMeta) {
+	var meta cacheMeta
+	if f.CacheDir == "" {
+		return nil, meta
+	}
+	path := f.cachePath(rawURL)
+	raw, err := os.ReadFile(path + ".json")
+	if err != nil || json.Unmarshal(raw, &meta) != nil || meta.URL != rawURL {
+		return nil, cacheMeta{}
+	}
+	data, err := os.ReadFile(path + ".data")
+	if err != nil {
+		return nil, cacheMeta{}
+	}
+	return data, meta
+}
+
+// writeCache stores data as the cached copy of rawURL. The data is written before the
+// metadata, so an interrupted write never pairs new validators with old contents.
+// Old validators with new contents merely cause a download on the next fetch.
+func (f *Fetcher) writeCache(rawURL string, data []byte, meta cacheMeta) error {
+	if f.CacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(f.CacheDir, 0o755); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := f.cachePath(rawURL)
+	if err := writeFileAtomic(path+".data", data); err != nil {
+		return err
+	}
+	return writeFileAtomic(path+".json", raw)
+}
+
+// urlPath returns the path of rawURL, e.g. for the file extension, or rawURL itself
+// if it can't be parsed.
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
@@ -0,0 +1,107 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetcherCache(t *testing.T) {
+	bank, etag := "5+5,10\n", `"v1"`
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(bank))
+	}))
+	defer srv.Close()
+
+	var warnings bytes.Buffer
+	f := &Fetcher{Client: srv.Client(), CacheDir: t.TempDir(), Warnings: &warnings}
+	url := srv.URL + "/banks/math.csv"
+	fetch := func() string {
+		t.Helper()
+		data, err := f.Fetch(context.Background(), url)
+		if err != nil {
+			t.Fatalf("Fetch returned error: %v", err)
+		}
+		return string(data)
+	}
+
+	if got := fetch(); got != bank {
+		t.Errorf("Expected %q on the first fetch, but got %q", bank, got)
+	}
+	// The second fetch is answered from the cache
+	if got := fetch(); got != "5+5,10\n" || notModified != 1 {
+		t.Errorf("Expected the cached bank after a 304, but got %q with %d 304s", got, notModified)
+	}
+	// A changed bank is downloaded again
+	bank, etag = "1+1,2\n", `"v2"`
+	if got := fetch(); got != bank {
+		t.Errorf("Expected the changed bank %q, but got %q", bank, got)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, but got %d", requests)
+	}
+
+	// Offline, the last cached copy is used
+	srv.Close()
+	if got := fetch(); got != bank {
+		t.Errorf("Expected the cached bank %q when offline, but got %q", bank, got)
+	}
+	if !strings.Contains(warnings.String(), "using the cached copy") {
+		t.Errorf("Expected a warning about the cached copy, but got %q", warnings.String())
+	}
+}
+
+func TestFetcherLastModified(t *testing.T) {
+	const modified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		w.Write([]byte("5+5,10\n"))
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client(), CacheDir: t.TempDir()}
+	for i := 0; i < 2; i++ {
+		if data, err := f.Fetch(context.Background(), srv.URL); err != nil || string(data) != "5+5,10\n" {
+			t.Errorf("Fetch %d returned %q, %v", i+1, data, err)
+		}
+	}
+}
+
+func TestFetcherErrors(t *testing.T) {
+	status := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client(), CacheDir: t.TempDir()}
+	if _, err := f.Fetch(context.Background(), srv.URL); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected an error for a missing bank, but got %v", err)
+	}
+	// Without a cached copy a server error can't be worked around
+	status = http.StatusInternalServerError
+	if _, err := f.Fetch(context.Background(), srv.URL); err == nil {
+		t.Error("Expected an error for a server error without a cached copy")
+	}
+}
+
+func TestFormatFromURL(t *testing.T) {
+	if got := FormatFromPath("https://example.com/banks/math.YAML?token=abc"); got != "yaml" {
+		t.Errorf("Expected the format from the URL path, but got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Commands a player can type instead of an answer to pause and save a session.
+const (
+	PauseCommand  = ":pause"  // Stops the timer until the session is resumed
+	ResumeCommand = ":resume" // Continues a paused session
+	SaveCommand   = ":save"   // Saves the session to its save file and ends it
+)
+
+// SessionState is the saved state of an unfinished session, as written by the
+// :save command and read back by ResumeSession.
+type SessionState struct {
+	Quiz      string          `json:"quiz"`      // Name of the quiz, as set by WithQuiz
+	QuizHash  string          `json:"quiz_hash"` // Hash of the question bank, as set by WithQuiz
+	Problems  []problemRecord `json:"problems"`  // Every problem of the session, in order
+	Outcomes  []Outcome       `json:"outcomes"`  // The problems answered so far
+	Remaining time.Duration   `json:"remaining"` // Time left, 0 if the session has no time limit
+	Adaptive  bool            `json:"adaptive"`
+	Scoring   ScoringPolicy   `json:"scoring"`
+}
+
+// WithSaveFile returns a SessionOption that enables the :save command, which writes
+// the session state to path so it can be continued with ResumeSession.
+func WithSaveFile(path string) SessionOption {
+	return func(s *Session) {
+		s.savePath = path
+	}
+}
+
+// WithQuiz returns a SessionOption that sets the name and hash of the quiz, which are
+// kept in saved sessions so the finished run can be recorded after resuming.
+func WithQuiz(name, hash string) SessionOption {
+	return func(s *Session) {
+		s.quiz, s.quizHash = name, hash
+	}
+}
+
+// Quiz returns the name and hash of the quiz set by WithQuiz or restored by ResumeSession.
+func (s *Session) Quiz() (name, hash string) {
+	return s.quiz, s.quizHash
+}
+
+// ResumeSession loads a session saved to path. It continues with the remaining
+// problems, the answers given so far, the time that was left and the original
+// scoring, and saves back to path. opts are applied on top, e.g. to set the input.
+func ResumeSession(path string, opts ...SessionOption) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the session file %s: %v", path, err)
+	}
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse the session file %s: %v", path, err)
+	}
+	problems, err := buildProblems(state.Problems, "session")
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSession(problems, WithTimeLimit(state.Remaining), WithScoring(state.Scoring), WithSaveFile(path), WithQuiz(state.Quiz, state.QuizHash))
+	s.adaptive = state.Adaptive
+	s.done = state.Outcomes
+	// Apply each option to configure the session
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// save writes the state of the running session to its save file.
+func (s *Session) save(outcomes []Outcome, remaining time.Duration) error {
+	if s.savePath == "" {
+		return fmt.Errorf("saving is not enabled for this session")
+	}
+	state := SessionState{
+		Quiz:      s.quiz,
+		QuizHash:  s.quizHash,
+		Problems:  make([]problemRecord, len(s.problems)),
+		Outcomes:  outcomes,
+		Remaining: remaining,
+		Adaptive:  s.adaptive,
+		Scoring:   s.scoring,
+	}
+	for i, p := range s.problems {
+		state.Problems[i] = problemRecord{
+			Question:   p.Question,
+			Answer:     p.Answer,
+			Difficulty: p.Difficulty,
+			Tags:       p.Tags,
+			Hint:       p.Hint,
+			Weight:     p.Weight,
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the session: %v", err)
+	}
+	if err := writeFileAtomic(s.savePath, data); err != nil {
+		return fmt.Errorf("failed to save the session: %v", err)
+	}
+	return nil
+}
+
+// remainingProblems returns the problems that have no outcome yet, in their order.
+// A question that appears several times is removed once per outcome.
+func remainingProblems(problems []Problem, outcomes []Outcome) []Problem {
+	answered := make(map[string]int)
+	for _, o := range outcomes {
+		answered[o.Question]++
+	}
+	var ret []Problem
+	for _, p := range problems {
+		if answered[p.Question] > 0 {
+			answered[p.Question]--
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
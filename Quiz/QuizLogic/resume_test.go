@@ -0,0 +1,63 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionSaveAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(strings.NewReader("10\n:pause\n:save\n")), WithOutput(&out),
+		WithTimeLimit(time.Minute), WithSaveFile(path), WithQuiz("sample", "abc"))
+
+	res := s.Run(context.Background())
+	if !res.Saved || res.Correct != 1 || len(res.Outcomes) != 1 {
+		t.Fatalf("Expected a saved session with 1 correct answer, but got %+v", res)
+	}
+	for _, want := range []string{"Paused with 1m0s left.", "Session saved to " + path} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "You answered") {
+		t.Errorf("Expected no summary for a saved session, but got %q", out.String())
+	}
+
+	out.Reset()
+	resumed, err := ResumeSession(path, WithInput(strings.NewReader("3\n10\n")), WithOutput(&out))
+	if err != nil {
+		t.Fatalf("ResumeSession returned error: %v", err)
+	}
+	if name, hash := resumed.Quiz(); name != "sample" || hash != "abc" {
+		t.Errorf("Expected the quiz to be restored, but got %q, %q", name, hash)
+	}
+	if resumed.limit <= 0 || resumed.limit > time.Minute {
+		t.Errorf("Expected the remaining time as the time limit, but got %v", resumed.limit)
+	}
+
+	res = resumed.Run(context.Background())
+	if res.Saved || res.Correct != 2 || res.Wrong() != 1 || len(res.Outcomes) != 3 {
+		t.Errorf("Expected the resumed session to finish with 2 correct and 1 wrong, but got %+v", res)
+	}
+	if !strings.HasPrefix(out.String(), "Problem #2: 1+1 = ") {
+		t.Errorf("Expected the resumed session to continue with problem #2, but got %q", out.String())
+	}
+}
+
+func TestSessionResumeCommand(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(strings.NewReader("10\n:pause\n2\n:resume\n2\n:save\n10\n")), WithOutput(&out))
+
+	res := s.Run(context.Background())
+	if res.Correct != 3 || res.Saved {
+		t.Errorf("Expected answers during the pause to be ignored and 3 correct answers, but got %+v", res)
+	}
+	if !strings.Contains(out.String(), "Failed to save the session: saving is not enabled") {
+		t.Errorf("Expected saving to fail without a save file, but got %q", out.String())
+	}
+}
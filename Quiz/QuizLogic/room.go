@@ -0,0 +1,493 @@
+package quiz
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Message types exchanged between a room and its participants.
+const (
+	MsgWelcome    = "welcome"    // Sent to a participant after joining
+	MsgError      = "error"      // Sent when joining fails, followed by closing the connection
+	MsgQuestion   = "question"   // A new question, pushed to everyone at the same time
+	MsgAnswer     = "answer"     // A participant's answer to the current question
+	MsgResult     = "result"     // The correct answer and the points a participant earned
+	MsgScoreboard = "scoreboard" // The live scoreboard after each question
+	MsgEnd        = "end"        // The final scoreboard, sent before closing the connection
+)
+
+// maxPoints is awarded for a correct answer given instantly. Answers given just before
+// the time runs out earn half of it.
+const maxPoints = 1000
+
+// codeAlphabet excludes characters that are easily confused when read aloud.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Message is the JSON envelope for everything sent over a room's WebSocket connections.
+type Message struct {
+	Type     string  `json:"type"`
+	Index    int     `json:"index,omitempty"`    // 1-based index of the question
+	Total    int     `json:"total,omitempty"`    // Number of questions in the room
+	Question string  `json:"question,omitempty"` // Question text
+	Answer   string  `json:"answer,omitempty"`   // Given answer, or the correct one in a result
+	Seconds  int     `json:"seconds,omitempty"`  // Time limit of the question
+	Correct  bool    `json:"correct,omitempty"`  // Whether the participant's answer was correct
+	Points   int     `json:"points,omitempty"`   // Points earned on the question
+	Scores   []Score `json:"scores,omitempty"`   // Scoreboard, best first
+	Error    string  `json:"error,omitempty"`
+}
+
+// Score is a participant's standing on the scoreboard.
+type Score struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+}
+
+// Server hosts multiplayer quiz rooms. Participants join a room by connecting a
+// WebSocket to /join?code=<room code>&name=<player name>.
+type Server struct {
+	mu       sync.Mutex
+	rooms    map[string]*Room
+	upgrader websocket.Upgrader
+}
+
+// Room is a single multiplayer quiz in which every participant gets the same
+// question at the same time.
+type Room struct {
+	Code string
+
+	server       *Server // Server the room is registered with
+	problems     []Problem
+	questionTime time.Duration
+	out          io.Writer // The host's view of the room
+	match        Matcher   // How answers are compared with the expected ones
+
+	mu       sync.Mutex
+	players  map[string]*player
+	order    []string // Player names in the order they joined
+	started  bool
+	current  int           // Index of the open question, -1 if none is open
+	asked    time.Time     // When the open question was pushed
+	answered int           // Number of answers to the open question
+	allIn    chan struct{} // Signalled when every connected player has answered
+	gone     chan struct{} // Closed when every player has left the started room
+
+	pumps sync.WaitGroup // Running write pumps, waited for so final messages are delivered
+}
+
+// player is a participant connected to a room.
+type player struct {
+	name      string
+	conn      *websocket.Conn
+	send      chan Message
+	closeOnce sync.Once
+
+	// Guarded by the room's mutex
+	connected bool
+	points    int
+	answered  bool // Whether the open question was answered
+	correct   bool // Whether that answer was correct
+	gained    int  // Points earned on the open question
+}
+
+// RoomOption represents a functional option for configuring a Room.
+type RoomOption func(r *Room)
+
+// WithRoomMatcher sets how a room compares answers with the expected ones, so that it
+// accepts the same answers as a session with the same Matcher. Rooms match exactly
+// by default.
+func WithRoomMatcher(m Matcher) RoomOption {
+	return func(r *Room) {
+		r.match = m
+	}
+}
+
+// NewServer creates a Server without any rooms.
+func NewServer() *Server {
+	return &Server{rooms: make(map[string]*Room)}
+}
+
+// NewRoom creates a room for the given problems with a time limit per question, and
+// registers it under a new random code. Host events are written to out. The room is
+// removed from the server once its game ends, which is early if every player left it.
+func (s *Server) NewRoom(problems []Problem, questionTime time.Duration, out io.Writer, opts ...RoomOption) *Room {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := newRoomCode()
+	for s.rooms[code] != nil {
+		code = newRoomCode()
+	}
+	r := &Room{
+		Code:         code,
+		server:       s,
+		problems:     problems,
+		questionTime: questionTime,
+		out:          out,
+		players:      make(map[string]*player),
+		current:      -1,
+		gone:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	s.rooms[code] = r
+	return r
+}
+
+// removeRoom unregisters r, freeing its code for a new room.
+func (s *Server) removeRoom(r *Room) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rooms[r.Code] == r {
+		delete(s.rooms, r.Code)
+	}
+}
+
+// ServeHTTP upgrades join requests to WebSocket connections and adds the participant
+// to the requested room. Failures are reported with an error message before closing.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/join" {
+		http.NotFound(w, req)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return // The upgrader already replied with an HTTP error
+	}
+
+	code, name := req.URL.Query().Get("code"), req.URL.Query().Get("name")
+	s.mu.Lock()
+	r := s.rooms[code]
+	s.mu.Unlock()
+
+	if r == nil {
+		err = fmt.Errorf("no room with code %q", code)
+	} else {
+		err = r.join(name, conn)
+	}
+	if err != nil {
+		conn.WriteJSON(Message{Type: MsgError, Error: err.Error()})
+		conn.Close()
+	}
+}
+
+// join adds a participant to the room and starts its connection pumps.
+func (r *Room) join(name string, conn *websocket.Conn) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case name == "":
+		return fmt.Errorf("a player name is required")
+	case r.started:
+		return fmt.Errorf("room %s has already started", r.Code)
+	case r.players[name] != nil:
+		return fmt.Errorf("the name %q is already taken", name)
+	}
+
+	p := &player{name: name, conn: conn, send: make(chan Message, 16), connected: true}
+	r.players[name] = p
+	r.order = append(r.order, name)
+	p.send <- Message{Type: MsgWelcome, Total: len(r.problems), Seconds: int(r.questionTime / time.Second)}
+	fmt.Fprintf(r.out, "%s joined (%d players)\n", name, len(r.players))
+
+	r.pumps.Add(1)
+	go func() {
+		defer r.pumps.Done()
+		p.writePump()
+	}()
+	go r.readPump(p)
+	return nil
+}
+
+// Players returns the names of the connected participants in the order they joined.
+func (r *Room) Players() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for _, name := range r.order {
+		if r.players[name].connected {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Run closes the room to new participants and asks every question in turn. A question
+// ends when its time runs out or every connected participant has answered. Correct
+// answers earn more points the faster they are given. Run ends early once every
+// participant has left, and returns the final scores.
+func (r *Room) Run(ctx context.Context) []Score {
+	r.mu.Lock()
+	r.started = true
+	r.signalIfGoneLocked()
+	r.mu.Unlock()
+
+	for i, p := range r.problems {
+		allIn := r.open(i)
+		r.broadcast(Message{Type: MsgQuestion, Index: i + 1, Total: len(r.problems), Question: p.Question, Seconds: int(r.questionTime / time.Second)})
+		fmt.Fprintf(r.out, "Problem #%d: %s\n", i+1, p.Question)
+
+		timer := time.NewTimer(r.questionTime)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return r.finish()
+		case <-r.gone:
+			timer.Stop()
+			fmt.Fprintln(r.out, "Every player left.")
+			return r.finish()
+		case <-timer.C:
+		case <-allIn:
+			timer.Stop()
+		}
+		r.close(p.Answer)
+
+		scores := r.Scores()
+		r.broadcast(Message{Type: MsgScoreboard, Index: i + 1, Total: len(r.problems), Scores: scores})
+		printScores(r.out, scores)
+	}
+	return r.finish()
+}
+
+// Scores returns the current scoreboard, best first.
+func (r *Room) Scores() []Score {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scores := make([]Score, 0, len(r.order))
+	for _, name := range r.order {
+		scores = append(scores, Score{Name: name, Points: r.players[name].points})
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Points > scores[j].Points
+	})
+	return scores
+}
+
+// open makes question i the open question and returns the channel that is signalled
+// once every connected player has answered it.
+func (r *Room) open(i int) <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current, r.asked, r.answered = i, time.Now(), 0
+	r.allIn = make(chan struct{}, 1)
+	for _, p := range r.players {
+		p.answered, p.correct, p.gained = false, false, 0
+	}
+	return r.allIn
+}
+
+// close ends the open question and tells every player the correct answer and the
+// points they earned.
+func (r *Room) close(answer string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.players {
+		p.points += p.gained
+		r.sendLocked(p, Message{Type: MsgResult, Index: r.current + 1, Answer: answer, Correct: p.correct, Points: p.gained})
+	}
+	r.current = -1
+}
+
+// submit records a player's answer to question index (1-based). Answers to questions
+// that are no longer open and second answers to the same question are ignored.
+func (r *Room) submit(p *player, index int, answer string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current < 0 || index != r.current+1 || p.answered {
+		return
+	}
+	p.answered = true
+	if r.match.Match(answer, r.problems[r.current].Answer) {
+		p.correct = true
+		p.gained = speedPoints(time.Since(r.asked), r.questionTime)
+	}
+	r.answered++
+	r.signalIfAllInLocked()
+}
+
+// leave marks a player as disconnected so the room doesn't wait for their answers. A
+// started room that the last player left ends its game. A room that hasn't started
+// stays open, so players can still join or reconnect.
+func (r *Room) leave(p *player) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !p.connected {
+		return
+	}
+	p.connected = false
+	p.close()
+	if !r.started {
+		delete(r.players, p.name) // Free the name for a reconnect before the start
+		for i, name := range r.order {
+			if name == p.name {
+				r.order = append(r.order[:i], r.order[i+1:]...)
+				break
+			}
+		}
+	}
+	fmt.Fprintf(r.out, "%s left\n", p.name)
+	r.signalIfAllInLocked()
+	r.signalIfGoneLocked()
+}
+
+// signalIfGoneLocked closes gone if the room has started and no player is connected
+// anymore. The caller must hold r.mu.
+func (r *Room) signalIfGoneLocked() {
+	if !r.started || r.connectedLocked() > 0 {
+		return
+	}
+	select {
+	case <-r.gone:
+	default:
+		close(r.gone)
+	}
+}
+
+// connectedLocked returns the number of connected players. The caller must hold r.mu.
+func (r *Room) connectedLocked() int {
+	n := 0
+	for _, p := range r.players {
+		if p.connected {
+			n++
+		}
+	}
+	return n
+}
+
+// signalIfAllInLocked signals allIn if every connected player answered the open question.
+// The caller must hold r.mu.
+func (r *Room) signalIfAllInLocked() {
+	if r.current < 0 {
+		return
+	}
+	for _, p := range r.players {
+		if p.connected && !p.answered {
+			return
+		}
+	}
+	select {
+	case r.allIn <- struct{}{}:
+	default:
+	}
+}
+
+// broadcast sends msg to every connected player.
+func (r *Room) broadcast(msg Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.players {
+		r.sendLocked(p, msg)
+	}
+}
+
+// sendLocked queues msg for p without blocking. A player that can't keep up is
+// disconnected. The caller must hold r.mu.
+func (r *Room) sendLocked(p *player, msg Message) {
+	if !p.connected {
+		return
+	}
+	select {
+	case p.send <- msg:
+	default:
+		p.connected = false
+		p.close()
+	}
+}
+
+// finish sends the final scoreboard, disconnects every player, waits until the queued
+// messages have been written and removes the room from the server.
+func (r *Room) finish() []Score {
+	defer r.server.removeRoom(r)
+	scores := r.Scores()
+	r.broadcast(Message{Type: MsgEnd, Total: len(r.problems), Scores: scores})
+
+	r.mu.Lock()
+	for _, p := range r.players {
+		p.connected = false
+		p.close()
+	}
+	r.mu.Unlock()
+	r.pumps.Wait()
+	return scores
+}
+
+// readPump reads messages from a player until the connection is closed.
+func (r *Room) readPump(p *player) {
+	defer r.leave(p)
+	for {
+		var msg Message
+		if err := p.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		if msg.Type == MsgAnswer {
+			r.submit(p, msg.Index, msg.Answer)
+		}
+	}
+}
+
+// writePump writes queued messages to a player and closes the connection once the
+// queue is closed.
+func (p *player) writePump() {
+	defer p.conn.Close()
+	for msg := range p.send {
+		p.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := p.conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// close closes the player's send queue, which makes writePump close the connection.
+func (p *player) close() {
+	p.closeOnce.Do(func() {
+		close(p.send)
+	})
+}
+
+// speedPoints returns the points for a correct answer given after elapsed, scaling
+// from maxPoints for an instant answer down to half of it at the time limit.
+func speedPoints(elapsed, limit time.Duration) int {
+	if limit <= 0 {
+		return maxPoints
+	}
+	ratio := math.Min(1, float64(elapsed)/float64(limit))
+	return int(math.Round(maxPoints * (1 - ratio/2)))
+}
+
+// newRoomCode returns a random six character room code.
+func newRoomCode() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b)
+}
+
+// printScores writes a scoreboard as a numbered list.
+func printScores(w io.Writer, scores []Score) {
+	for i, s := range scores {
+		fmt.Fprintf(w, "  %d. %s - %d\n", i+1, s.Name, s.Points)
+	}
+}
@@ -0,0 +1,253 @@
+package quiz
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startRoom starts a quiz server with a single room and returns the room and the
+// WebSocket address participants connect to.
+func startRoom(t *testing.T, problems []Problem, questionTime time.Duration, opts ...RoomOption) (*Room, string) {
+	t.Helper()
+	srv := NewServer()
+	room := srv.NewRoom(problems, questionTime, io.Discard, opts...)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return room, "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+// waitForPlayers waits until n participants have joined the room.
+func waitForPlayers(t *testing.T, r *Room, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(r.Players()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d players to join, but got %v", n, r.Players())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// answerAll plays a room as a participant, answering every question with answers[i].
+func answerAll(p *Participant, answers []string) ([]Score, error) {
+	for {
+		msg, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch msg.Type {
+		case MsgQuestion:
+			if err := p.Answer(msg.Index, answers[msg.Index-1]); err != nil {
+				return nil, err
+			}
+		case MsgEnd:
+			return msg.Scores, nil
+		}
+	}
+}
+
+func TestRoom(t *testing.T) {
+	room, addr := startRoom(t, []Problem{{Question: "5+5", Answer: "10"}, {Question: "1+1", Answer: "2"}}, 5*time.Second)
+	ctx := context.Background()
+
+	alice, err := JoinRoom(ctx, addr, room.Code, "alice")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	defer alice.Close()
+	bob, err := JoinRoom(ctx, addr, room.Code, "bob")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	defer bob.Close()
+	if alice.Total != 2 {
+		t.Errorf("Expected the room to announce 2 questions, but got %d", alice.Total)
+	}
+	if _, err := JoinRoom(ctx, addr, room.Code, "bob"); err == nil {
+		t.Errorf("Expected joining with a taken name to fail")
+	}
+	if _, err := JoinRoom(ctx, addr, "NOPE", "carol"); err == nil {
+		t.Errorf("Expected joining an unknown room to fail")
+	}
+	waitForPlayers(t, room, 2)
+
+	type played struct {
+		scores []Score
+		err    error
+	}
+	aliceDone, bobDone := make(chan played), make(chan played)
+	go func() {
+		scores, err := answerAll(alice, []string{"10", "2"})
+		aliceDone <- played{scores, err}
+	}()
+	go func() {
+		scores, err := answerAll(bob, []string{"10", "3"})
+		bobDone <- played{scores, err}
+	}()
+
+	// Both players answer right away, so no question waits for its time limit.
+	start := time.Now()
+	final := room.Run(ctx)
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("Expected questions to end once everyone answered, but the room ran for %v", elapsed)
+	}
+
+	if len(final) != 2 || final[0].Name != "alice" || final[0].Points <= final[1].Points || final[1].Points == 0 {
+		t.Errorf("Expected alice ahead of bob with points for both, but got %+v", final)
+	}
+	for _, done := range []chan played{aliceDone, bobDone} {
+		p := <-done
+		if p.err != nil {
+			t.Fatalf("Participant returned error: %v", p.err)
+		}
+		if len(p.scores) != 2 || p.scores[0] != final[0] {
+			t.Errorf("Expected participants to receive the final scores %+v, but got %+v", final, p.scores)
+		}
+	}
+
+	if _, err := JoinRoom(ctx, addr, room.Code, "late"); err == nil {
+		t.Errorf("Expected joining a started room to fail")
+	}
+}
+
+func TestRoomMatcherAndRemoval(t *testing.T) {
+	room, addr := startRoom(t, []Problem{{Question: "Largest city of Switzerland?", Answer: "Zürich"}}, 5*time.Second, WithRoomMatcher(NormalizedMatcher))
+	ctx := context.Background()
+
+	p, err := JoinRoom(ctx, addr, room.Code, "raz")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	defer p.Close()
+	waitForPlayers(t, room, 1)
+	go answerAll(p, []string{"zurich"})
+
+	if final := room.Run(ctx); final[0].Points == 0 {
+		t.Errorf("Expected the normalized answer to earn points, but got %+v", final)
+	}
+	room.server.mu.Lock()
+	defer room.server.mu.Unlock()
+	if _, ok := room.server.rooms[room.Code]; ok {
+		t.Errorf("Expected the finished room to be removed from the server")
+	}
+}
+
+func TestRoomAbandoned(t *testing.T) {
+	room, addr := startRoom(t, []Problem{{Question: "5+5", Answer: "10"}, {Question: "1+1", Answer: "2"}}, time.Hour)
+	ctx := context.Background()
+
+	p, err := JoinRoom(ctx, addr, room.Code, "raz")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	waitForPlayers(t, room, 1)
+	go func() {
+		p.Next() // Leave once the first question is asked
+		p.Close()
+	}()
+
+	done := make(chan []Score)
+	go func() {
+		done <- room.Run(ctx)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the room to finish once its last player left, but it kept waiting")
+	}
+
+	room.server.mu.Lock()
+	defer room.server.mu.Unlock()
+	if _, ok := room.server.rooms[room.Code]; ok {
+		t.Errorf("Expected the abandoned room to be removed from the server")
+	}
+}
+
+func TestRoomTimeLimit(t *testing.T) {
+	room, addr := startRoom(t, []Problem{{Question: "5+5", Answer: "10"}}, 50*time.Millisecond)
+	ctx := context.Background()
+
+	idle, err := JoinRoom(ctx, addr, room.Code, "idle")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	defer idle.Close()
+	waitForPlayers(t, room, 1)
+
+	// The question closes after its time limit even though nobody answered.
+	final := room.Run(ctx)
+	if len(final) != 1 || final[0].Points != 0 {
+		t.Errorf("Expected no points for an unanswered question, but got %+v", final)
+	}
+}
+
+func TestParticipantPlay(t *testing.T) {
+	room, addr := startRoom(t, []Problem{{Question: "5+5", Answer: "10"}}, 5*time.Second)
+	ctx := context.Background()
+
+	p, err := JoinRoom(ctx, addr, room.Code, "raz")
+	if err != nil {
+		t.Fatalf("JoinRoom returned error: %v", err)
+	}
+	defer p.Close()
+	waitForPlayers(t, room, 1)
+
+	in, answers := io.Pipe()
+	defer answers.Close()
+	out := &chanWriter{lines: make(chan string, 16)}
+	done := make(chan error)
+	go func() {
+		_, err := p.Play(ctx, in, out)
+		done <- err
+	}()
+	go room.Run(ctx)
+
+	// Answer once the question shows up
+	for line := range out.lines {
+		if strings.Contains(line, "Problem #1 of 1") {
+			io.WriteString(answers, "10\n")
+			break
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Play returned error: %v", err)
+	}
+	if scores := room.Scores(); scores[0].Points == 0 {
+		t.Errorf("Expected points for the correct answer, but got %+v", scores)
+	}
+}
+
+// chanWriter is an io.Writer that hands every write to a channel.
+type chanWriter struct {
+	lines chan string
+}
+
+func (b *chanWriter) Write(p []byte) (int, error) {
+	select {
+	case b.lines <- string(p):
+	default:
+	}
+	return len(p), nil
+}
+
+func TestSpeedPoints(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{0, 1000},
+		{5 * time.Second, 750},
+		{10 * time.Second, 500},
+		{time.Minute, 500},
+	}
+
+	for _, tt := range tests {
+		if got := speedPoints(tt.elapsed, 10*time.Second); got != tt.want {
+			t.Errorf("speedPoints(%v, 10s) = %d; want %d", tt.elapsed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package quiz
+
+import (
+	"math"
+	"strings"
+)
+
+// HintRequest is the answer that reveals the hint of the current problem.
+const HintRequest = "?"
+
+// PartSeparator separates the parts of a multi-part answer, e.g. "red;green;blue".
+const PartSeparator = ";"
+
+// ScoringPolicy decides how many points an answer is worth. Each problem is worth
+// its weight (1 unless set in the question bank) when answered correctly.
+type ScoringPolicy struct {
+	HintPenalty   float64 // Share of the points lost by revealing the hint, from 0 to 1
+	PartialCredit bool    // Award a share of the points per correct part of a multi-part answer
+	WrongPenalty  float64 // Share of the weight subtracted for a wrong answer (negative marking)
+	Match         Matcher // How answers are compared with the expected ones, exactly if zero
+	RejectEcho    bool    // Count answers that repeat a calculation from the question, e.g. "5+5", as wrong
+}
+
+// DefaultScoring is the policy of a session without WithScoring: hints cost half the
+// points, multi-part answers must be fully correct and wrong answers cost nothing.
+var DefaultScoring = ScoringPolicy{HintPenalty: 0.5}
+
+// score returns the points for answering p with answer, and whether the answer is
+// fully correct. hinted reports whether the player revealed the hint first.
+func (sp ScoringPolicy) score(p Problem, answer string, hinted bool) (float64, bool) {
+	credit := 0.0
+	echo := sp.RejectEcho && isEcho(answer, p.Question)
+	correct := !echo && sp.Match.Match(answer, p.Answer)
+	if correct {
+		credit = 1
+	} else if parts := answerParts(p.Answer); !echo && len(parts) > 1 {
+		credit = partsCredit(answerParts(answer), parts, sp.Match)
+		correct = credit == 1
+		if !correct && !sp.PartialCredit {
+			credit = 0
+		}
+	}
+
+	if credit == 0 {
+		return -sp.WrongPenalty * weightOf(p), false
+	}
+	if hinted {
+		credit *= 1 - sp.HintPenalty
+	}
+	return credit * weightOf(p), correct
+}
+
+// answerParts splits a multi-part answer into its trimmed parts.
+func answerParts(s string) []string {
+	parts := strings.Split(s, PartSeparator)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// partsCredit returns the share of the wanted parts that were given at the same
+// position, compared with m.
+func partsCredit(given, want []string, m Matcher) float64 {
+	matched := 0
+	for i := range want {
+		if i < len(given) && m.Match(given[i], want[i]) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(want))
+}
+
+// weightOf returns the weight of p, or 1 if it isn't weighted.
+func weightOf(p Problem) float64 {
+	if p.Weight == 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+// roundPoints rounds points to two decimal places for display.
+func roundPoints(points float64) float64 {
+	return math.Round(points*100) / 100
+}
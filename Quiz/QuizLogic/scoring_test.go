@@ -0,0 +1,69 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScoringPolicy(t *testing.T) {
+	colors := Problem{Question: "Primary colors?", Answer: "red;green;blue", Weight: 3}
+	tests := []struct {
+		policy  ScoringPolicy
+		p       Problem
+		answer  string
+		hinted  bool
+		points  float64
+		correct bool
+	}{
+		{DefaultScoring, Problem{Question: "5+5", Answer: "10"}, "10", false, 1, true},
+		{DefaultScoring, Problem{Question: "5+5", Answer: "10"}, "10", true, 0.5, true},
+		{DefaultScoring, Problem{Question: "5+5", Answer: "10"}, "11", false, 0, false},
+		{DefaultScoring, Problem{Question: "5+5", Answer: "10", Weight: 2}, "10", false, 2, true},
+		{DefaultScoring, colors, "red; green ;blue", false, 3, true},
+		{DefaultScoring, colors, "red;green;yellow", false, 0, false},
+		{ScoringPolicy{PartialCredit: true}, colors, "red;green;yellow", false, 2, false},
+		{ScoringPolicy{PartialCredit: true, HintPenalty: 0.5}, colors, "red", true, 0.5, false},
+		{ScoringPolicy{PartialCredit: true, WrongPenalty: 0.25}, colors, "blue;red", false, -0.75, false},
+		{ScoringPolicy{WrongPenalty: 0.25}, Problem{Question: "5+5", Answer: "10"}, "11", true, -0.25, false},
+		{ScoringPolicy{Match: FuzzyMatcher}, Problem{Question: "Capital of Spain?", Answer: "Madrid"}, "madrd", false, 1, true},
+		{ScoringPolicy{PartialCredit: true, Match: NormalizedMatcher}, colors, "Red;GREEN;yellow", false, 2, false},
+	}
+
+	for _, tt := range tests {
+		points, correct := tt.policy.score(tt.p, tt.answer, tt.hinted)
+		if points != tt.points || correct != tt.correct {
+			t.Errorf("%+v.score(%q, %q, %v) = %v, %v; want %v, %v",
+				tt.policy, tt.p.Answer, tt.answer, tt.hinted, points, correct, tt.points, tt.correct)
+		}
+	}
+}
+
+func TestSessionHints(t *testing.T) {
+	problems := []Problem{
+		{Question: "5+5", Answer: "10", Hint: "Count your fingers"},
+		{Question: "1+1", Answer: "2"},
+		{Question: "7+3", Answer: "10", Weight: 2},
+	}
+	var out bytes.Buffer
+	s := NewSession(problems, WithInput(strings.NewReader("?\n10\n?\n2\n11\n")), WithOutput(&out),
+		WithScoring(ScoringPolicy{HintPenalty: 0.5, WrongPenalty: 0.5}))
+
+	res := s.Run(context.Background())
+	if res.Correct != 2 || res.Score != 0.5 || res.MaxScore != 4 {
+		t.Errorf("Expected 2 correct and 0.5 of 4 points, but got %+v", res)
+	}
+	if !res.Outcomes[0].Hinted || res.Outcomes[1].Hinted {
+		t.Errorf("Expected only the first answer to be hinted, but got %+v", res.Outcomes)
+	}
+	for _, want := range []string{
+		"Problem #1: 5+5 = Hint: Count your fingers\nProblem #1: 5+5 = ",
+		"There is no hint for this problem.\nProblem #2: 1+1 = ",
+		"Your score: 0.5 of 4 points.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package quiz
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Session runs a quiz over a set of problems, reading answers from an io.Reader
+// and writing prompts and results to an io.Writer.
+type Session struct {
+	problems []Problem
+	in       io.Reader
+	out      io.Writer
+	limit    time.Duration
+	expired  <-chan time.Time // Overrides limit when set (used by RunQuiz)
+	adaptive bool
+	scoring  ScoringPolicy
+	savePath string    // Where :save writes the session, empty if disabled
+	done     []Outcome // Outcomes restored by ResumeSession
+	quiz     string
+	quizHash string
+	clock    ClockMode
+	warnings []time.Duration
+	answers  <-chan string // Input shared by the turns of a hot-seat game, read from in if nil
+	msg      *message.Printer
+}
+
+// SessionOption represents a functional option for configuring a Session.
+type SessionOption func(s *Session)
+
+// Result holds the outcome of a quiz session.
+type Result struct {
+	Correct    int             // Number of correctly answered problems
+	Total      int             // Number of problems in the session
+	TimedOut   bool            // Whether the time limit ran out before the last problem
+	Duration   time.Duration   // How long the session ran
+	Outcomes   []Outcome       // One entry per problem asked, in order, including one cut off by the time limit
+	Skill      float64         // Estimated skill level in adaptive mode, 0 otherwise
+	Categories []CategoryScore // Score per tag, nil if no problem is tagged
+	Score      float64         // Points scored under the scoring policy
+	MaxScore   float64         // Points for answering every problem correctly without hints
+	Saved      bool            // Whether the session was saved to be resumed later
+}
+
+// Outcome records how a single problem was answered.
+type Outcome struct {
+	Question string        // The question that was asked
+	Answer   string        // The answer given by the player
+	Correct  bool          // Whether the answer was correct
+	Elapsed  time.Duration // Time taken to answer
+	Tags     []string      // Tags of the problem
+	Hinted   bool          // Whether the hint was revealed before answering
+	Points   float64       // Points scored for the answer
+	TimedOut bool          // Whether the time ran out while the problem was asked
+}
+
+// Wrong returns the number of problems that were not answered correctly,
+// including the ones that were never reached.
+func (r Result) Wrong() int {
+	return r.Total - r.Correct
+}
+
+// NewSession creates a new Session for the given problems. By default it reads
+// from os.Stdin, writes to os.Stdout and has no time limit.
+//
+// Example usage:
+//
+//	s := NewSession(problems, WithInput(r), WithOutput(w), WithTimeLimit(30*time.Second))
+//	res := s.Run(ctx)
+func NewSession(problems []Problem, opts ...SessionOption) *Session {
+	s := &Session{
+		problems: problems,
+		in:       os.Stdin,
+		out:      os.Stdout,
+		scoring:  DefaultScoring,
+		msg:      newPrinter(language.English),
+	}
+	// Apply each option to configure the session
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithInput returns a SessionOption that sets the reader answers are read from.
+func WithInput(r io.Reader) SessionOption {
+	return func(s *Session) {
+		s.in = r
+	}
+}
+
+// WithOutput returns a SessionOption that sets the writer prompts and results are written to.
+func WithOutput(w io.Writer) SessionOption {
+	return func(s *Session) {
+		s.out = w
+	}
+}
+
+// WithTimeLimit returns a SessionOption that sets the time limit for the whole quiz.
+// A zero or negative duration disables the limit.
+func WithTimeLimit(d time.Duration) SessionOption {
+	return func(s *Session) {
+		s.limit = d
+	}
+}
+
+// WithAdaptive returns a SessionOption that picks each next problem by the player's
+// recent correctness and response time instead of asking them in order. Problems get
+// harder after streaks and easier after misses, and the result includes an estimate
+// of the player's skill level.
+func WithAdaptive() SessionOption {
+	return func(s *Session) {
+		s.adaptive = true
+	}
+}
+
+// WithScoring returns a SessionOption that sets the policy answers are scored with.
+// Without it a session uses DefaultScoring.
+func WithScoring(policy ScoringPolicy) SessionOption {
+	return func(s *Session) {
+		s.scoring = policy
+	}
+}
+
+// WithClock returns a SessionOption that shows the time left on the time limit in
+// front of each prompt. LiveClock keeps it ticking with ANSI escapes, and should only
+// be used when the output is a terminal.
+func WithClock(mode ClockMode) SessionOption {
+	return func(s *Session) {
+		s.clock = mode
+	}
+}
+
+// WithWarnings returns a SessionOption that warns the player when the time left on
+// the time limit drops below each of the given thresholds.
+func WithWarnings(thresholds ...time.Duration) SessionOption {
+	return func(s *Session) {
+		s.warnings = thresholds
+	}
+}
+
+// WithLanguage returns a SessionOption that sets the language of the prompts and
+// messages, one of Languages (see ParseLanguage). Without it a session uses English.
+func WithLanguage(tag language.Tag) SessionOption {
+	return func(s *Session) {
+		s.msg = newPrinter(tag)
+	}
+}
+
+// Run asks each problem in turn until all of them are answered, the time limit
+// runs out, the input is exhausted or ctx is cancelled, and returns the result.
+func (s *Session) Run(ctx context.Context) (res Result) {
+	defer func(start time.Time) {
+		res.Duration = time.Since(start)
+	}(time.Now())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Stops the input reader once the session is over
+
+	// The session's own time limit can be paused, a timer passed to RunQuiz can't
+	expired := s.expired
+	var cd *countdown
+	if expired == nil && s.limit > 0 {
+		cd = newCountdown(s.limit)
+		defer cd.Stop()
+		expired = cd.C()
+	}
+
+	// Show the time left and update it every second
+	var (
+		clk  *clock
+		tick <-chan time.Time
+	)
+	if cd != nil && (s.clock != NoClock || len(s.warnings) > 0) {
+		clk = newClock(s.out, s.msg, cd, s.clock, s.warnings)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// A resumed session continues with the problems that have not been answered yet
+	pool := remainingProblems(s.problems, s.done)
+	var sel selector = &sequential{problems: pool}
+	if s.adaptive {
+		a := newAdaptive(pool)
+		for _, o := range s.done {
+			a.record(Problem{Question: o.Question, Difficulty: difficultyOf(s.problems, o.Question)}, o)
+		}
+		defer func() {
+			res.Skill = a.skill
+			if !res.Saved {
+				s.msg.Fprintf(s.out, msgSkill, a.skill, MaxDifficulty)
+			}
+		}()
+		sel = a
+	}
+
+	// Report the score per category after the summary
+	defer func() {
+		res.Categories = categoryScores(s.problems, res.Outcomes)
+		if !res.Saved {
+			printCategories(s.out, s.msg, res.Categories)
+		}
+	}()
+
+	// Report the points right after the summary when they tell more than the count
+	defer func() {
+		if !res.Saved && (res.Score != float64(res.Correct) || res.MaxScore != float64(res.Total)) {
+			s.msg.Fprintf(s.out, msgScore, roundPoints(res.Score), roundPoints(res.MaxScore))
+		}
+	}()
+
+	answers := s.answers
+	if answers == nil {
+		answers = readAnswers(ctx, s.in)
+	}
+	res.Total = len(s.problems)
+	for _, p := range s.problems {
+		res.MaxScore += weightOf(p)
+	}
+	for _, o := range s.done {
+		if o.Correct {
+			res.Correct++
+		}
+		res.Score += o.Points
+		res.Outcomes = append(res.Outcomes, o)
+	}
+	for i := len(s.done); ; i++ { // Iterate through the questions
+		p, ok := sel.next()
+		if !ok {
+			break
+		}
+		s.prompt(clk, i+1, p)
+		asked := time.Now()
+		hinted := false
+		for answered := false; !answered; { // Hints are asked for on the same prompt
+			select {
+			case <-expired: // Time's up
+				res.TimedOut = true
+				res.Outcomes = append(res.Outcomes, Outcome{Question: p.Question, Elapsed: time.Since(asked), Tags: p.Tags, TimedOut: true})
+				s.msg.Fprintf(s.out, msgTimeUp)
+				s.stop(res)
+				return res
+			case <-tick: // Update the clock
+				if clk.tick() {
+					s.prompt(clk, i+1, p)
+				}
+			case <-ctx.Done(): // Cancelled by the caller
+				fmt.Fprintln(s.out)
+				s.stop(res)
+				return res
+			case answer, ok := <-answers: // Check the user's answer
+				if !ok { // No more input, nothing left to answer with
+					fmt.Fprintln(s.out)
+					s.stop(res)
+					return res
+				}
+				switch strings.TrimSpace(answer) {
+				case HintRequest:
+					if p.Hint == "" {
+						s.msg.Fprintf(s.out, msgNoHint)
+					} else {
+						hinted = true
+						s.msg.Fprintf(s.out, msgHint, p.Hint)
+					}
+					s.prompt(clk, i+1, p)
+					continue
+				case PauseCommand:
+					paused := time.Now()
+					cmd, ok := s.pause(ctx, answers, cd)
+					asked = asked.Add(time.Since(paused)) // The pause doesn't count as answer time
+					if !ok {
+						fmt.Fprintln(s.out)
+						s.stop(res)
+						return res
+					}
+					if cmd == ResumeCommand {
+						s.prompt(clk, i+1, p)
+						continue
+					}
+					fallthrough // Saving from the pause
+				case SaveCommand:
+					if s.saveAndStop(&res, cd) {
+						return res
+					}
+					s.prompt(clk, i+1, p)
+					continue
+				}
+				o := Outcome{Question: p.Question, Answer: answer, Elapsed: time.Since(asked), Tags: p.Tags, Hinted: hinted}
+				o.Points, o.Correct = s.scoring.score(p, answer, hinted)
+				if o.Correct {
+					res.Correct++
+				}
+				res.Score += o.Points
+				res.Outcomes = append(res.Outcomes, o)
+				sel.record(p, o)
+				answered = true
+			}
+		}
+	}
+	s.summary(res)
+	return res
+}
+
+// prompt asks problem p, numbered n, after the time left on clk if any.
+func (s *Session) prompt(clk *clock, n int, p Problem) {
+	clk.prefix()
+	s.msg.Fprintf(s.out, msgProblem, n, p.Question)
+}
+
+// pause stops the countdown and waits until the player resumes or saves the session.
+// It returns the command that ended the pause, and false if the input ran out or ctx
+// was cancelled first.
+func (s *Session) pause(ctx context.Context, answers <-chan string, cd *countdown) (string, bool) {
+	if cd != nil {
+		cd.Pause()
+		defer cd.Resume()
+		s.msg.Fprintf(s.out, msgPausedLeft, cd.Remaining().Round(time.Second))
+	} else {
+		s.msg.Fprintf(s.out, msgPaused)
+	}
+	s.msg.Fprintf(s.out, msgPauseHelp, ResumeCommand, SaveCommand)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", false
+		case answer, ok := <-answers:
+			if !ok {
+				return "", false
+			}
+			if cmd := strings.TrimSpace(answer); cmd == ResumeCommand || cmd == SaveCommand {
+				return cmd, true
+			}
+		}
+	}
+}
+
+// saveAndStop saves the session with the outcomes in res and the time left on cd.
+// It reports whether the session was saved and should end; on failure the player
+// can keep answering.
+func (s *Session) saveAndStop(res *Result, cd *countdown) bool {
+	var remaining time.Duration
+	if cd != nil {
+		remaining = cd.Remaining()
+	}
+	if err := s.save(res.Outcomes, remaining); err != nil {
+		s.msg.Fprintf(s.out, msgSaveFailed, err)
+		return false
+	}
+	res.Saved = true
+	s.msg.Fprintf(s.out, msgSaved, s.savePath)
+	return true
+}
+
+// stop prints the summary for a session that ended before the last problem.
+func (s *Session) stop(res Result) {
+	s.summary(res)
+}
+
+// summary prints the number of correct and wrong answers.
+func (s *Session) summary(res Result) {
+	s.msg.Fprintf(s.out, msgSummary, res.Correct, res.Wrong())
+}
+
+// readAnswers starts a single goroutine that scans lines from r and delivers them
+// on the returned channel. The channel is closed once r is exhausted or ctx is done.
+// A Read that is already blocked (e.g. on a terminal) returns with the next line,
+// after which the goroutine notices ctx and exits.
+func readAnswers(ctx context.Context, r io.Reader) <-chan string {
+	answers := make(chan string)
+	go func() {
+		defer close(answers)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case answers <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return answers
+}
@@ -0,0 +1,77 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sessionProblems = []Problem{
+	{Question: "5+5", Answer: "10"},
+	{Question: "1+1", Answer: "2"},
+	{Question: "7+3", Answer: "10"},
+}
+
+func TestSessionRun(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(strings.NewReader("10\n3\n 10 \n")), WithOutput(&out))
+
+	res := s.Run(context.Background())
+	if res.Correct != 2 || res.Wrong() != 1 || res.TimedOut {
+		t.Errorf("Expected 2 correct, 1 wrong and no timeout, but got %+v", res)
+	}
+	if len(res.Outcomes) != 3 || res.Outcomes[1].Question != "1+1" || res.Outcomes[1].Answer != "3" || res.Outcomes[1].Correct {
+		t.Errorf("Expected an outcome per answered problem, but got %+v", res.Outcomes)
+	}
+	for _, want := range []string{"Problem #1: 5+5 = ", "Problem #3: 7+3 = ", "You answered 2 questions correctly and got 1 wrong."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, but got %q", want, out.String())
+		}
+	}
+}
+
+func TestSessionTimeLimit(t *testing.T) {
+	// A pipe that is never written to blocks like an idle terminal.
+	r, w := io.Pipe()
+	defer w.Close()
+
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(r), WithOutput(&out), WithTimeLimit(10*time.Millisecond))
+
+	res := s.Run(context.Background())
+	if !res.TimedOut || res.Correct != 0 {
+		t.Errorf("Expected a timed out session with no correct answers, but got %+v", res)
+	}
+	if len(res.Outcomes) != 1 || !res.Outcomes[0].TimedOut || res.Outcomes[0].Question != sessionProblems[0].Question {
+		t.Errorf("Expected the unanswered question to be recorded as timed out, but got %+v", res.Outcomes)
+	}
+	if !strings.Contains(out.String(), "Time's up!") {
+		t.Errorf("Expected output to announce the timeout, but got %q", out.String())
+	}
+}
+
+func TestSessionInputClosed(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSession(sessionProblems, WithInput(strings.NewReader("10\n")), WithOutput(&out))
+
+	res := s.Run(context.Background())
+	if res.Correct != 1 || res.Wrong() != 2 || res.TimedOut {
+		t.Errorf("Expected 1 correct and 2 wrong, but got %+v", res)
+	}
+}
+
+func TestSessionCancel(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := NewSession(sessionProblems, WithInput(r), WithOutput(io.Discard))
+	if res := s.Run(ctx); res.Correct != 0 || res.TimedOut {
+		t.Errorf("Expected a cancelled session with no answers, but got %+v", res)
+	}
+}
@@ -0,0 +1,156 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default SM-2 parameters.
+const (
+	InitialEase = 2.5 // Ease factor of a card that has never been reviewed
+	MinEase     = 1.3 // Lowest ease factor a card can reach
+
+	fastAnswer = 5 * time.Second  // Correct answers at least this fast are graded as perfect
+	slowAnswer = 20 * time.Second // Correct answers slower than this are graded as difficult
+)
+
+// Card tracks the SM-2 schedule of a single question for one user.
+type Card struct {
+	Ease        float64   `json:"ease"`        // Ease factor, never below MinEase
+	Interval    int       `json:"interval"`    // Days until the next review
+	Repetitions int       `json:"repetitions"` // Consecutive successful reviews
+	Due         time.Time `json:"due"`         // When the card should be reviewed next
+}
+
+// Progress holds the study progress of every user, keyed by user name and question.
+// It is persisted as a JSON file between runs.
+type Progress struct {
+	path  string
+	Users map[string]map[string]*Card `json:"users"`
+}
+
+// LoadProgress reads the progress file at path. A missing file yields empty progress
+// that will be created on the first Save.
+func LoadProgress(path string) (*Progress, error) {
+	p := &Progress{path: path, Users: make(map[string]map[string]*Card)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the progress file %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse the progress file %s: %v", path, err)
+	}
+	if p.Users == nil {
+		p.Users = make(map[string]map[string]*Card)
+	}
+	return p, nil
+}
+
+// Save writes the progress back to the file it was loaded from. The file is replaced
+// atomically so an interrupted save never leaves a truncated file behind.
+func (p *Progress) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %v", err)
+	}
+	if err := writeFileAtomic(p.path, data); err != nil {
+		return fmt.Errorf("failed to save progress: %v", err)
+	}
+	return nil
+}
+
+// Due returns the problems that are due for review by user at now, in their original
+// order. Problems the user has never seen are always due.
+func (p *Progress) Due(user string, problems []Problem, now time.Time) []Problem {
+	var due []Problem
+	for _, pr := range problems {
+		if c, ok := p.Users[user][pr.Question]; !ok || !c.Due.After(now) {
+			due = append(due, pr)
+		}
+	}
+	return due
+}
+
+// NextDue returns the earliest due date among the given problems that are not yet
+// due for user, and false if there are none.
+func (p *Progress) NextDue(user string, problems []Problem, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, pr := range problems {
+		c, ok := p.Users[user][pr.Question]
+		if ok && c.Due.After(now) && (next.IsZero() || c.Due.Before(next)) {
+			next = c.Due
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Review updates the card of question for user with a review of the given quality,
+// from 0 (complete blackout) to 5 (perfect response), using the SM-2 algorithm.
+func (p *Progress) Review(user, question string, quality int, now time.Time) {
+	cards, ok := p.Users[user]
+	if !ok {
+		cards = make(map[string]*Card)
+		p.Users[user] = cards
+	}
+	c, ok := cards[question]
+	if !ok {
+		c = &Card{Ease: InitialEase}
+		cards[question] = c
+	}
+
+	if quality >= 3 { // Successful recall grows the interval
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetitions++
+	} else { // Failed recall starts the card over
+		c.Repetitions = 0
+		c.Interval = 1
+	}
+
+	q := float64(5 - quality)
+	c.Ease = math.Max(MinEase, c.Ease+0.1-q*(0.08+q*0.02))
+	c.Due = now.AddDate(0, 0, c.Interval)
+}
+
+// ReviewSession reviews every problem of a session's outcomes for user, graded with
+// Grade. A problem cut off by the time limit was never answered, so it isn't reviewed
+// and stays due like the problems that were never reached.
+func (p *Progress) ReviewSession(user string, outcomes []Outcome, now time.Time) {
+	for _, o := range outcomes {
+		if !o.TimedOut {
+			p.Review(user, o.Question, Grade(o), now)
+		}
+	}
+}
+
+// Grade converts the outcome of an answered problem into an SM-2 quality score.
+// Fast correct answers are perfect, slow or hinted ones difficult, and wrong answers fail.
+func Grade(o Outcome) int {
+	switch {
+	case !o.Correct && strings.TrimSpace(o.Answer) == "":
+		return 0
+	case !o.Correct:
+		return 1
+	case o.Hinted:
+		return 3
+	case o.Elapsed <= fastAnswer:
+		return 5
+	case o.Elapsed <= slowAnswer:
+		return 4
+	default:
+		return 3
+	}
+}
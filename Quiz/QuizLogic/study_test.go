@@ -0,0 +1,115 @@
+package quiz
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReviewSchedule(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	p := &Progress{Users: make(map[string]map[string]*Card)}
+
+	// Successful reviews grow the interval 1, 6, then by the ease factor.
+	wantIntervals := []int{1, 6, 16}
+	for i, want := range wantIntervals {
+		p.Review("raz", "5+5", 5, now)
+		c := p.Users["raz"]["5+5"]
+		if c.Interval != want {
+			t.Errorf("Review %d: expected interval %d, but got %d", i+1, want, c.Interval)
+		}
+		if !c.Due.Equal(now.AddDate(0, 0, want)) {
+			t.Errorf("Review %d: expected due date %v, but got %v", i+1, now.AddDate(0, 0, want), c.Due)
+		}
+	}
+
+	// A failed review starts the card over and lowers its ease.
+	before := p.Users["raz"]["5+5"].Ease
+	p.Review("raz", "5+5", 1, now)
+	c := p.Users["raz"]["5+5"]
+	if c.Interval != 1 || c.Repetitions != 0 || c.Ease >= before {
+		t.Errorf("Expected the card to start over with a lower ease, but got %+v", c)
+	}
+
+	// The ease factor never drops below MinEase.
+	for i := 0; i < 10; i++ {
+		p.Review("raz", "5+5", 0, now)
+	}
+	if c.Ease != MinEase {
+		t.Errorf("Expected ease %v, but got %v", MinEase, c.Ease)
+	}
+}
+
+func TestProgressDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "progress.json")
+	problems := []Problem{{Question: "5+5", Answer: "10"}, {Question: "1+1", Answer: "2"}}
+
+	p, err := LoadProgress(path)
+	if err != nil {
+		t.Fatalf("LoadProgress returned error: %v", err)
+	}
+	p.Review("raz", "5+5", 5, now)
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	// Reload to make sure the schedule survives between runs.
+	p, err = LoadProgress(path)
+	if err != nil {
+		t.Fatalf("LoadProgress returned error: %v", err)
+	}
+	due := p.Due("raz", problems, now)
+	if len(due) != 1 || due[0].Question != "1+1" {
+		t.Errorf("Expected only the unseen problem to be due, but got %v", due)
+	}
+	if due := p.Due("raz", problems, now.AddDate(0, 0, 1)); len(due) != 2 {
+		t.Errorf("Expected both problems to be due the next day, but got %v", due)
+	}
+	if due := p.Due("someone-else", problems, now); len(due) != 2 {
+		t.Errorf("Expected progress to be tracked per user, but got %v", due)
+	}
+	if next, ok := p.NextDue("raz", problems, now); !ok || !next.Equal(now.AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next review on %v, but got %v", now.AddDate(0, 0, 1), next)
+	}
+}
+
+func TestReviewSessionSkipsTimedOut(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	problems := []Problem{{Question: "5+5", Answer: "10"}, {Question: "1+1", Answer: "2"}}
+	p, err := LoadProgress(filepath.Join(t.TempDir(), "progress.json"))
+	if err != nil {
+		t.Fatalf("LoadProgress returned error: %v", err)
+	}
+
+	p.ReviewSession("raz", []Outcome{
+		{Question: "5+5", Answer: "10", Correct: true, Elapsed: time.Second},
+		{Question: "1+1", Elapsed: 30 * time.Second, TimedOut: true},
+	}, now)
+	if _, ok := p.Users["raz"]["1+1"]; ok {
+		t.Error("Expected the problem cut off by the time limit not to be reviewed")
+	}
+	if due := p.Due("raz", problems, now); len(due) != 1 || due[0].Question != "1+1" {
+		t.Errorf("Expected the problem cut off by the time limit to stay due, but got %v", due)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		outcome Outcome
+		want    int
+	}{
+		{Outcome{Answer: "10", Correct: true, Elapsed: 2 * time.Second}, 5},
+		{Outcome{Answer: "10", Correct: true, Elapsed: 10 * time.Second}, 4},
+		{Outcome{Answer: "10", Correct: true, Elapsed: time.Minute}, 3},
+		{Outcome{Answer: "10", Correct: true, Elapsed: 2 * time.Second, Hinted: true}, 3},
+		{Outcome{Answer: "11", Correct: false}, 1},
+		{Outcome{Answer: " ", Correct: false}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Grade(tt.outcome); got != tt.want {
+			t.Errorf("Grade(%+v) = %d; want %d", tt.outcome, got, tt.want)
+		}
+	}
+}
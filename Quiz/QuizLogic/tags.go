@@ -0,0 +1,121 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/message"
+)
+
+// Untagged is the category problems without tags are reported under when other
+// problems in the same session have tags.
+const Untagged = "untagged"
+
+// CategoryScore holds the score of a session for a single tag.
+type CategoryScore struct {
+	Category string
+	Correct  int
+	Total    int
+}
+
+// ParseTags splits a list of tags separated by '|' or ',' and normalizes them to
+// lower case, as used in question banks and the -tags flags.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		if tag = normalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// FilterTags returns the problems that have at least one of the include tags (or all
+// problems if include is empty) and none of the exclude tags, in their original order.
+func FilterTags(problems []Problem, include, exclude []string) []Problem {
+	var ret []Problem
+	for _, p := range problems {
+		if (len(include) == 0 || hasAnyTag(p, include)) && !hasAnyTag(p, exclude) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// hasAnyTag reports whether p is tagged with any of tags.
+func hasAnyTag(p Problem, tags []string) bool {
+	for _, want := range tags {
+		for _, tag := range p.Tags {
+			if tag == normalizeTag(want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeTag trims and lower-cases a tag so tags match regardless of spelling.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// categoryScores computes the score per tag of a session over problems. A problem
+// with several tags counts towards each of them. It returns nil if no problem is tagged.
+func categoryScores(problems []Problem, outcomes []Outcome) []CategoryScore {
+	tagged := false
+	for _, p := range problems {
+		if len(p.Tags) > 0 {
+			tagged = true
+			break
+		}
+	}
+	if !tagged {
+		return nil
+	}
+
+	scores := make(map[string]*CategoryScore)
+	add := func(tags []string, correct, total int) {
+		if len(tags) == 0 {
+			tags = []string{Untagged}
+		}
+		for _, tag := range tags {
+			cs, ok := scores[tag]
+			if !ok {
+				cs = &CategoryScore{Category: tag}
+				scores[tag] = cs
+			}
+			cs.Correct += correct
+			cs.Total += total
+		}
+	}
+	for _, p := range problems {
+		add(p.Tags, 0, 1)
+	}
+	for _, o := range outcomes {
+		if o.Correct {
+			add(o.Tags, 1, 0)
+		}
+	}
+
+	ret := make([]CategoryScore, 0, len(scores))
+	for _, cs := range scores {
+		ret = append(ret, *cs)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Category < ret[j].Category
+	})
+	return ret
+}
+
+// printCategories writes the per-category breakdown of a session, if there is one.
+func printCategories(w io.Writer, msg *message.Printer, scores []CategoryScore) {
+	if len(scores) == 0 {
+		return
+	}
+	msg.Fprintf(w, msgCategoryTitle)
+	for _, cs := range scores {
+		fmt.Fprintf(w, "  %s: %d/%d\n", cs.Category, cs.Correct, cs.Total)
+	}
+}
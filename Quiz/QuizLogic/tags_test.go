@@ -0,0 +1,83 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var taggedProblems = []Problem{
+	{Question: "5+5", Answer: "10", Tags: []string{"math"}},
+	{Question: "Capital of France?", Answer: "Paris", Tags: []string{"geography", "europe"}},
+	{Question: "Capital of Peru?", Answer: "Lima", Tags: []string{"geography"}},
+	{Question: "Color of the sky?", Answer: "blue"},
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"math", []string{"math"}},
+		{" Math | Geography ", []string{"math", "geography"}},
+		{"a,b||c", []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTags(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseTags(%q) = %v; want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		expected         []string
+	}{
+		{nil, nil, []string{"5+5", "Capital of France?", "Capital of Peru?", "Color of the sky?"}},
+		{[]string{"Geography"}, nil, []string{"Capital of France?", "Capital of Peru?"}},
+		{[]string{"geography"}, []string{"europe"}, []string{"Capital of Peru?"}},
+		{nil, []string{"geography"}, []string{"5+5", "Color of the sky?"}},
+		{[]string{"history"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, p := range FilterTags(taggedProblems, tt.include, tt.exclude) {
+			got = append(got, p.Question)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("FilterTags(%v, %v) = %v; want %v", tt.include, tt.exclude, got, tt.expected)
+		}
+	}
+}
+
+func TestSessionCategories(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSession(taggedProblems, WithInput(strings.NewReader("10\nParis\nQuito\n")), WithOutput(&out))
+
+	res := s.Run(context.Background())
+	expected := []CategoryScore{
+		{Category: "europe", Correct: 1, Total: 1},
+		{Category: "geography", Correct: 1, Total: 2},
+		{Category: "math", Correct: 1, Total: 1},
+		{Category: Untagged, Correct: 0, Total: 1},
+	}
+	if !reflect.DeepEqual(res.Categories, expected) {
+		t.Errorf("Expected categories %+v, but got %+v", expected, res.Categories)
+	}
+	if !strings.Contains(out.String(), "Score by category:\n  europe: 1/1\n  geography: 1/2\n") {
+		t.Errorf("Expected a per-category breakdown in the output, but got %q", out.String())
+	}
+
+	// Untagged quizzes have no breakdown.
+	out.Reset()
+	res = NewSession(sessionProblems, WithInput(strings.NewReader("10\n")), WithOutput(&out)).Run(context.Background())
+	if res.Categories != nil || strings.Contains(out.String(), "Score by category:") {
+		t.Errorf("Expected no categories for untagged problems, but got %+v", res.Categories)
+	}
+}
@@ -0,0 +1,125 @@
+package quiz
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// Shuffle returns a copy of problems in a random order determined by seed. The same
+// seed always yields the same order, so a printed worksheet and a terminal quiz
+// shuffled with the same seed ask the problems in the same order.
+func Shuffle(problems []Problem, seed int64) []Problem {
+	ret := append([]Problem(nil), problems...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(ret), func(i, j int) {
+		ret[i], ret[j] = ret[j], ret[i]
+	})
+	return ret
+}
+
+// Worksheet is a printable version of a quiz: numbered questions with room for the
+// answers, followed by a separate answer key.
+type Worksheet struct {
+	Title    string
+	Note     string // Printed below the title, e.g. the shuffle seed
+	Problems []Problem
+}
+
+// worksheetHTML renders a Worksheet as a standalone HTML page. The answer key starts
+// on a new page when printed.
+var worksheetHTML = template.Must(template.New("worksheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.name { margin-bottom: 2em; }
+ol li { margin-bottom: 1.5em; }
+.blank { display: inline-block; width: 12em; border-bottom: 1px solid black; margin-left: 1em; }
+.key { page-break-before: always; }
+.key li { margin-bottom: 0.3em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Note}}<p>{{.Note}}</p>
+{{end}}<p class="name">Name: <span class="blank"></span> Date: <span class="blank"></span></p>
+<ol>
+{{range .Problems}}<li>{{.Question}} <span class="blank"></span></li>
+{{end}}</ol>
+<div class="key">
+<h2>Answer Key</h2>
+<ol>
+{{range .Problems}}<li>{{.Answer}}</li>
+{{end}}</ol>
+</div>
+</body>
+</html>
+`))
+
+// WriteHTML writes the worksheet as an HTML page.
+func (ws Worksheet) WriteHTML(w io.Writer) error {
+	if err := worksheetHTML.Execute(w, ws); err != nil {
+		return fmt.Errorf("failed to write the worksheet: %v", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the worksheet as a Markdown document. The answer key follows
+// a horizontal rule.
+func (ws Worksheet) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(ws.Title))
+	if ws.Note != "" {
+		fmt.Fprintf(&b, "%s\n\n", escapeMarkdown(ws.Note))
+	}
+	b.WriteString("Name: ____________________ Date: ____________\n\n")
+	for i, p := range ws.Problems {
+		fmt.Fprintf(&b, "%d. %s\n\n   Answer: ____________________\n\n", i+1, escapeMarkdown(p.Question))
+	}
+	b.WriteString("---\n\n## Answer Key\n\n")
+	for i, p := range ws.Problems {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, escapeMarkdown(p.Answer))
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write the worksheet: %v", err)
+	}
+	return nil
+}
+
+// WorksheetWriter returns the method that writes a worksheet in the given format:
+// "html", or "md"/"markdown".
+func WorksheetWriter(format string) (func(Worksheet, io.Writer) error, error) {
+	switch strings.ToLower(format) {
+	case "html", "htm":
+		return Worksheet.WriteHTML, nil
+	case "md", "markdown":
+		return Worksheet.WriteMarkdown, nil
+	default:
+		return nil, fmt.Errorf("unsupported worksheet format: %q. Please use html or md", format)
+	}
+}
+
+// Write writes the worksheet in the given format, as accepted by WorksheetWriter.
+func (ws Worksheet) Write(w io.Writer, format string) error {
+	write, err := WorksheetWriter(format)
+	if err != nil {
+		return err
+	}
+	return write(ws, w)
+}
+
+// markdownEscaper escapes the characters that would otherwise format worksheet text.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// escapeMarkdown escapes s for use as plain text in Markdown.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,80 @@
+package quiz
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var worksheetProblems = []Problem{
+	{Question: "5*5", Answer: "25"},
+	{Question: "Is 1 < 2?", Answer: "yes"},
+	{Question: "7+3", Answer: "10"},
+}
+
+func TestShuffle(t *testing.T) {
+	problems := append(worksheetProblems, sessionProblems...)
+	a, b := Shuffle(problems, 42), Shuffle(problems, 42)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected the same order for the same seed, but got %v and %v", a, b)
+	}
+	if reflect.DeepEqual(a, problems) {
+		t.Errorf("Expected a different order than %v", problems)
+	}
+	if problems[0].Question != "5*5" {
+		t.Errorf("Expected Shuffle not to modify its input, but got %v", problems)
+	}
+
+	seen := make(map[string]int)
+	for _, p := range a {
+		seen[p.Question+"="+p.Answer]++
+	}
+	for _, p := range problems {
+		seen[p.Question+"="+p.Answer]--
+	}
+	for k, n := range seen {
+		if n != 0 {
+			t.Errorf("Expected every problem exactly once, but %q is off by %d", k, n)
+		}
+	}
+}
+
+func TestWorksheet(t *testing.T) {
+	ws := Worksheet{Title: "Week 1", Note: "Seed 42", Problems: worksheetProblems}
+
+	var html bytes.Buffer
+	if err := ws.Write(&html, "html"); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	for _, want := range []string{"<h1>Week 1</h1>", "<p>Seed 42</p>", "<li>Is 1 &lt; 2? ", "<h2>Answer Key</h2>", "<li>25</li>"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("Expected the HTML worksheet to contain %q, but got %q", want, html.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := ws.Write(&md, "md"); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	for _, want := range []string{"# Week 1\n", "1. 5\\*5\n", "2. Is 1 \\< 2?\n", "## Answer Key\n\n1. 25\n2. yes\n3. 10\n"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Expected the Markdown worksheet to contain %q, but got %q", want, md.String())
+		}
+	}
+
+	if err := ws.Write(&md, "pdf"); err == nil {
+		t.Error("Expected an error for an unsupported format, but got none")
+	}
+}
+
+func TestWorksheetWriter(t *testing.T) {
+	for _, format := range []string{"html", "HTM", "md", "markdown"} {
+		if _, err := WorksheetWriter(format); err != nil {
+			t.Errorf("Expected a writer for %q, but got error: %v", format, err)
+		}
+	}
+	if _, err := WorksheetWriter("pdf"); err == nil {
+		t.Error("Expected an error for an unsupported format, but got none")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// export implements the "export" subcommand, which renders a question bank as a
+// printable worksheet with a separate answer key.
+func export(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	csvPtr := fs.String("csv", "Problems.csv", "a question bank file")
+	formatPtr := fs.String("format", "", "the question bank format (csv, json, yaml, md, gift or apkg). Defaults to the file extension")
+	ankiFieldsPtr := fs.String("anki-fields", "1,2", "the note fields of an Anki deck used as question and answer, counting from 1")
+	outPtr := fs.String("o", "", "the file the worksheet is written to. Defaults to standard output")
+	asPtr := fs.String("as", "", "the worksheet format (html or md). Defaults to the output file extension, or html")
+	tagsPtr := fs.String("tags", "", "only export problems with one of these comma separated tags")
+	excludeTagsPtr := fs.String("exclude-tags", "", "skip problems with any of these comma separated tags")
+	titlePtr := fs.String("title", "", "the title of the worksheet. Defaults to the question bank file name")
+	shufflePtr := fs.Bool("shuffle", false, "shuffle the problems, in the same order as a quiz run with the same -seed")
+	seedPtr := fs.Int64("seed", 0, "the random seed for -shuffle. 0 picks a random one")
+	fs.Parse(args)
+
+	// Check the worksheet format before anything is loaded or written
+	as := *asPtr
+	if as == "" {
+		if as = quiz.FormatFromPath(*outPtr); as == "" {
+			as = "html"
+		}
+	}
+	write, err := quiz.WorksheetWriter(as)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	anki, err := ankiFields(*ankiFieldsPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	problems, _, err := quiz.LoadFile(*csvPtr, *formatPtr, anki)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *seedPtr == 0 {
+		*seedPtr = time.Now().UnixNano()
+	}
+	if problems, err = pickProblems(problems, *tagsPtr, *excludeTagsPtr, *shufflePtr, *seedPtr); err != nil {
+		log.Fatal(err)
+	}
+
+	ws := quiz.Worksheet{Title: *titlePtr, Problems: problems}
+	if ws.Title == "" {
+		ws.Title = strings.TrimSuffix(filepath.Base(*csvPtr), filepath.Ext(*csvPtr))
+	}
+	if *shufflePtr {
+		ws.Note = fmt.Sprintf("Seed %d", *seedPtr)
+	}
+
+	var w io.Writer = os.Stdout
+	if *outPtr != "" {
+		f, err := os.Create(*outPtr)
+		if err != nil {
+			log.Fatalf("Couldn't create the worksheet file %s: %v", *outPtr, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(ws, w); err != nil {
+		log.Fatal(err)
+	}
+}
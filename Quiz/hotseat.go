@@ -0,0 +1,37 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"context"
+	"log"
+	"strings"
+)
+
+// hotSeat runs a hot-seat game of problems for players on this terminal. Each finished
+// turn is recorded on the leaderboard database db (unless empty) under the player's
+// name, for the quiz with the given name and hash.
+func hotSeat(players []string, problems []quiz.Problem, name, hash, db string, opts ...quiz.SessionOption) {
+	standings := quiz.RunHotSeat(context.Background(), players, problems, opts...)
+	if db == "" {
+		return
+	}
+	for _, s := range standings {
+		if err := recordResult(db, s.Player, name, hash, s.Result); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// parsePlayers splits a comma separated list of player names, dropping empty and
+// repeated ones.
+func parsePlayers(list string) []string {
+	var players []string
+	seen := make(map[string]bool)
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" && !seen[p] {
+			seen[p] = true
+			players = append(players, p)
+		}
+	}
+	return players
+}
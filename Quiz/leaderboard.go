@@ -0,0 +1,107 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// leaderboard implements the "leaderboard" subcommand, which prints the top scores
+// of every recorded quiz (or a single one) and the personal bests of a player.
+func leaderboard(args []string) {
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	dbPtr := fs.String("db", "quiz.db", "the SQLite database runs are recorded in")
+	csvPtr := fs.String("csv", "", "only show the top scores of this question bank file")
+	userPtr := fs.String("user", os.Getenv("USER"), "the player whose personal bests are shown")
+	topPtr := fs.Int("top", 5, "the number of top scores shown per quiz")
+	fs.Parse(args)
+
+	db, err := sql.Open("sqlite", *dbPtr)
+	if err != nil {
+		log.Fatalf("Couldn't open SQLite database file %s: %v", *dbPtr, err)
+	}
+	defer db.Close()
+
+	lb, err := quiz.NewLeaderboard(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Determine which quizzes to show
+	var quizzes []quiz.QuizSummary
+	if *csvPtr != "" {
+		data, err := quiz.ReadSource(*csvPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		quizzes = []quiz.QuizSummary{{Hash: quiz.HashQuiz(data), Name: *csvPtr}}
+	} else if quizzes, err = lb.Quizzes(); err != nil {
+		log.Fatal(err)
+	}
+	if len(quizzes) == 0 {
+		fmt.Println("No runs recorded yet.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, q := range quizzes {
+		top, err := lb.Top(q.Hash, *topPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "\n== %s (%s) ==\n", q.Name, q.Hash)
+		fmt.Fprintln(w, "#\tPLAYER\tPOINTS\tCORRECT\tTIME\tDATE")
+		for i, r := range top {
+			fmt.Fprintf(w, "%d\t%s\t%g\t%d/%d\t%s\t%s\n", i+1, r.Player, r.Score, r.Correct, r.Total, r.Duration.Round(time.Second), r.FinishedAt.Format("2006-01-02"))
+		}
+	}
+
+	if *userPtr != "" {
+		bests, err := lb.PersonalBests(*userPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(w, "\n== Personal bests of %s ==\n", *userPtr)
+		fmt.Fprintln(w, "QUIZ\tPOINTS\tCORRECT\tTIME\tDATE")
+		for _, r := range bests {
+			fmt.Fprintf(w, "%s\t%g\t%d/%d\t%s\t%s\n", r.QuizName, r.Score, r.Correct, r.Total, r.Duration.Round(time.Second), r.FinishedAt.Format("2006-01-02"))
+		}
+	}
+	w.Flush()
+}
+
+// recordRun stores a finished run in the leaderboard database at path.
+func recordRun(path string, run quiz.Run) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("couldn't open SQLite database file %s: %v", path, err)
+	}
+	defer db.Close()
+
+	lb, err := quiz.NewLeaderboard(db)
+	if err != nil {
+		return err
+	}
+	return lb.Record(run)
+}
+
+// recordResult stores the result of a finished session by player in the leaderboard
+// database at path, under the quiz with the given name and hash.
+func recordResult(path, player, name, hash string, res quiz.Result) error {
+	return recordRun(path, quiz.Run{
+		Player:     player,
+		QuizHash:   hash,
+		QuizName:   name,
+		Score:      res.Score,
+		Correct:    res.Correct,
+		Total:      res.Total,
+		Duration:   res.Duration,
+		FinishedAt: time.Now(),
+		Outcomes:   res.Outcomes,
+	})
+}
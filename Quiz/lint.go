@@ -0,0 +1,52 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// lint implements the "lint" subcommand, which checks question bank files and prints
+// every issue with its line number. It exits with status 1 if any file has errors.
+func lint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	csvPtr := fs.String("csv", "Problems.csv", "a question bank file; more files can follow the flags")
+	formatPtr := fs.String("format", "", "the question bank format (csv, json, yaml, md, gift or apkg). Defaults to each file's extension")
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{*csvPtr}
+	}
+
+	errors, warnings := 0, 0
+	for _, file := range files {
+		format := *formatPtr
+		if format == "" {
+			format = quiz.FormatFromPath(file)
+		}
+		data, err := quiz.ReadSource(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		issues, err := quiz.Lint(data, format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", file, issue)
+			if issue.Severity == quiz.Error {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	fmt.Printf("%d error(s), %d warning(s)\n", errors, warnings)
+	if errors > 0 {
+		os.Exit(1)
+	}
+}
@@ -2,34 +2,314 @@ package main
 
 import (
 	quiz "Quiz/QuizLogic"
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/text/language"
 )
 
 // main is the entry point of the Quiz application.
 func main() {
-	// Command-line flags for CSV file path and quiz timer duration.
-	csvPtr := flag.String("csv", "Problems.csv", "a csv file in the format of 'question,answer'")
+	// Dispatch subcommands before parsing the quiz flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "leaderboard":
+			leaderboard(os.Args[2:])
+			return
+		case "host":
+			host(os.Args[2:])
+			return
+		case "join":
+			join(os.Args[2:])
+			return
+		case "lint":
+			lint(os.Args[2:])
+			return
+		case "stats":
+			stats(os.Args[2:])
+			return
+		case "export":
+			export(os.Args[2:])
+			return
+		}
+	}
+
+	// Command-line flags for the question bank, its format and quiz timer duration.
+	csvPtr := flag.String("csv", "Problems.csv", "a question bank file or http(s) URL; a csv file is in the format of 'question,answer'")
+	formatPtr := flag.String("format", "", "the question bank format (csv, json, yaml, md, gift or apkg). Defaults to the file extension")
 	timerPtr := flag.Int("timer", 30, "the time limit for the quiz in seconds")
+	adaptivePtr := flag.Bool("adaptive", false, "adaptive mode: pick each next problem by difficulty, based on recent answers")
+	studyPtr := flag.Bool("study", false, "study mode: only ask the problems that are due for review")
+	userPtr := flag.String("user", os.Getenv("USER"), "the player name used for the leaderboard and study progress")
+	progressPtr := flag.String("progress", "quiz_progress.json", "the file study progress is stored in")
+	dbPtr := flag.String("db", "quiz.db", "the SQLite database finished runs are recorded in for the leaderboard. Empty disables recording")
+	generatePtr := flag.String("generate", "", "generate arithmetic problems instead of loading a file, from a list of operations such as 'add,sub,mul,div'")
+	digitsPtr := flag.Int("digits", 0, "the number of digits per generated operand. Defaults to the difficulty's")
+	difficultyPtr := flag.String("difficulty", "easy", "the difficulty of generated problems (easy, medium or hard)")
+	countPtr := flag.Int("count", 10, "the number of problems to generate")
+	seedPtr := flag.Int64("seed", 0, "the random seed for generated problems and -shuffle. 0 picks a random one")
+	shufflePtr := flag.Bool("shuffle", false, "ask the problems in a random order; the same -seed gives the same order as the export subcommand")
+	negativePtr := flag.Bool("negative", false, "allow generated subtractions with negative answers")
+	fractionsPtr := flag.Bool("fractions", false, "allow generated divisions with a remainder, answered to two decimal places")
+	tagsPtr := flag.String("tags", "", "only ask problems with one of these comma separated tags")
+	excludeTagsPtr := flag.String("exclude-tags", "", "skip problems with any of these comma separated tags")
+	hintPenaltyPtr := flag.Float64("hint-penalty", quiz.DefaultScoring.HintPenalty, "the share of a problem's points lost by revealing its hint with '?'")
+	partialPtr := flag.Bool("partial", false, "award partial credit for each correct part of a multi-part answer separated by ';'")
+	wrongPenaltyPtr := flag.Float64("wrong-penalty", 0, "the share of a problem's points subtracted for a wrong answer")
+	matchPtr := flag.String("match", "exact", "how answers are compared: exact, normalized (ignoring case, accents and punctuation) or fuzzy (normalized, tolerating typos)")
+	typosPtr := flag.Float64("typos", quiz.DefaultTypoRate, "the typos tolerated per character of the answer with -match=fuzzy")
+	expressionsPtr := flag.Bool("expressions", false, "accept any arithmetic expression that evaluates to a numeric answer, e.g. '2*5' for 10")
+	rejectEchoPtr := flag.Bool("reject-echo", false, "with -expressions, count answers that repeat the question's calculation as wrong")
+	ankiFieldsPtr := flag.String("anki-fields", "1,2", "the note fields of an Anki deck used as question and answer, counting from 1")
+	savePtr := flag.String("save", "quiz_session.json", "the file a session is saved to with ':save', to be continued with -resume")
+	resumePtr := flag.String("resume", "", "continue the session saved in this file")
+	clockPtr := flag.Bool("clock", true, "show the time left in front of each prompt, updated live on a terminal")
+	playersPtr := flag.String("players", "", "hot-seat mode: comma separated players or teams taking turns on this terminal, each with the full timer")
+	langPtr := flag.String("lang", "", "the language of the quiz prompts and messages ("+strings.Join(quiz.Languages(), " or ")+"). Defaults to the system locale, or English")
+	warnPtr := flag.String("warn", "10", "warn when the time left drops below these comma separated numbers of seconds. Empty disables warnings")
 	flag.Parse()
 
-	// Open the specified CSV file.
-	file, err := os.Open(*csvPtr)
-	if err != nil {
-		log.Fatalf("Failed to open the CSV file: %s", *csvPtr)
+	if *hintPenaltyPtr < 0 || *hintPenaltyPtr > 1 || *wrongPenaltyPtr < 0 {
+		log.Fatal("The hint penalty must be from 0 to 1 and the wrong answer penalty must not be negative")
+	}
+	if *seedPtr == 0 {
+		*seedPtr = time.Now().UnixNano()
+	}
+	players := parsePlayers(*playersPtr)
+	if len(players) > 0 && (*studyPtr || *resumePtr != "") {
+		log.Fatal("Hot-seat mode can't be combined with -study or -resume")
 	}
-	defer file.Close() // Ensure file is closed after use.
 
-	// Parse the CSV file into a list of problems.
-	problems, err := quiz.ParseCSV(file)
+	display, err := displayOptions(*clockPtr, *warnPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	lang, err := quizLanguage(*langPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	display = append(display, quiz.WithLanguage(lang))
+	matcher, err := answerMatcher(*matchPtr, *typosPtr, *expressionsPtr)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Create a timer for the quiz duration.
-	timer := time.NewTimer(time.Duration(*timerPtr) * time.Second)
-	// Start the quiz with the parsed problems and timer.
-	quiz.RunQuiz(timer.C, problems)
+	// Continue a saved session instead of starting a new one.
+	if *resumePtr != "" {
+		resume(*resumePtr, *userPtr, *dbPtr, display...)
+		return
+	}
+
+	// Generate problems, or load the question bank determining its format by flag or extension.
+	var (
+		problems []quiz.Problem
+		data     []byte
+		name     = filepath.Base(*csvPtr)
+	)
+	if *generatePtr != "" {
+		cfg := quiz.GeneratorConfig{
+			Ops:            quiz.ParseOps(*generatePtr),
+			Digits:         *digitsPtr,
+			Difficulty:     *difficultyPtr,
+			Count:          *countPtr,
+			Seed:           *seedPtr,
+			AllowNegative:  *negativePtr,
+			AllowFractions: *fractionsPtr,
+		}
+		if problems, err = quiz.Generate(cfg); err != nil {
+			log.Fatal(err)
+		}
+		// Runs with the same settings share a leaderboard regardless of the seed
+		cfg.Seed = 0
+		data, name = []byte(fmt.Sprintf("%+v", cfg)), "generated "+*generatePtr
+	} else {
+		anki, err := ankiFields(*ankiFieldsPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if problems, data, err = quiz.LoadFile(*csvPtr, *formatPtr, anki); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Narrow the quiz down to the selected categories and shuffle it with the seed, so
+	// the order can be reproduced on paper.
+	if problems, err = pickProblems(problems, *tagsPtr, *excludeTagsPtr, *shufflePtr, *seedPtr); err != nil {
+		log.Fatal(err)
+	}
+	if *tagsPtr != "" || *excludeTagsPtr != "" {
+		// Filtered runs get their own leaderboard
+		data = append(data, fmt.Sprintf("\ntags=%s exclude=%s", *tagsPtr, *excludeTagsPtr)...)
+		label := *tagsPtr
+		if *excludeTagsPtr != "" {
+			label = strings.TrimSpace(label + " without " + *excludeTagsPtr)
+		}
+		name += " [" + label + "]"
+	}
+
+	if *shufflePtr {
+		fmt.Printf("Shuffled with seed %d.\n", *seedPtr)
+	}
+
+	// In study mode only the problems that are due for review are asked.
+	now := time.Now()
+	var progress *quiz.Progress
+	if *studyPtr {
+		progress, err = quiz.LoadProgress(*progressPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		due := progress.Due(*userPtr, problems, now)
+		if len(due) == 0 {
+			next, _ := progress.NextDue(*userPtr, problems, now)
+			fmt.Printf("Nothing to review, %s. The next review is due on %s.\n", *userPtr, next.Format("Mon Jan 2 15:04"))
+			return
+		}
+		fmt.Printf("%d of %d problems are due for review.\n", len(due), len(problems))
+		problems = due
+	}
+
+	// Start the quiz with the parsed problems and the quiz duration.
+	opts := []quiz.SessionOption{
+		quiz.WithTimeLimit(time.Duration(*timerPtr) * time.Second),
+		quiz.WithScoring(quiz.ScoringPolicy{
+			HintPenalty:   *hintPenaltyPtr,
+			PartialCredit: *partialPtr,
+			WrongPenalty:  *wrongPenaltyPtr,
+			Match:         matcher,
+			RejectEcho:    *rejectEchoPtr,
+		}),
+	}
+	opts = append(opts, display...)
+	if *adaptivePtr {
+		opts = append(opts, quiz.WithAdaptive())
+	}
+	if progress == nil {
+		opts = append(opts, quiz.WithSaveFile(*savePtr), quiz.WithQuiz(name, quiz.HashQuiz(data)))
+	}
+
+	// Players take turns on this terminal instead of a single run.
+	if len(players) > 0 {
+		hotSeat(players, problems, name, quiz.HashQuiz(data), *dbPtr, opts...)
+		return
+	}
+
+	s := quiz.NewSession(problems, opts...)
+	res := s.Run(context.Background())
+
+	// Record the study session; problems that were never answered stay due.
+	if progress != nil {
+		progress.ReviewSession(*userPtr, res.Outcomes, now)
+		if err := progress.Save(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Record the finished run on the leaderboard.
+	if *dbPtr != "" && !res.Saved {
+		if err := recordResult(*dbPtr, *userPtr, name, quiz.HashQuiz(data), res); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// ankiFields parses the -anki-fields flag, the question and answer field numbers of
+// an Anki deck counting from 1, such as "2,1".
+func ankiFields(s string) (quiz.LoadOption, error) {
+	var q, a int
+	if _, err := fmt.Sscanf(s, "%d,%d", &q, &a); err != nil || q < 1 || a < 1 {
+		return nil, fmt.Errorf("invalid Anki fields %q: use the question and answer field numbers, e.g. '1,2'", s)
+	}
+	return quiz.WithAnkiFields(q-1, a-1), nil
+}
+
+// pickProblems narrows problems down to those with one of the comma separated tags
+// and none of the excluded ones, then shuffles them with seed if shuffle is set. Quiz
+// runs and worksheets both pick their problems this way, so the same flags give the
+// same problems in the same order.
+func pickProblems(problems []quiz.Problem, tags, excludeTags string, shuffle bool, seed int64) ([]quiz.Problem, error) {
+	if tags != "" || excludeTags != "" {
+		problems = quiz.FilterTags(problems, quiz.ParseTags(tags), quiz.ParseTags(excludeTags))
+		if len(problems) == 0 {
+			return nil, fmt.Errorf("no problems match the selected tags")
+		}
+	}
+	if shuffle {
+		problems = quiz.Shuffle(problems, seed)
+	}
+	return problems, nil
+}
+
+// answerMatcher returns the matcher for the -match, -typos and -expressions flags.
+func answerMatcher(mode string, typos float64, expressions bool) (quiz.Matcher, error) {
+	matcher, err := quiz.MatcherFor(mode)
+	if err != nil {
+		return matcher, err
+	}
+	if matcher.TypoRate > 0 {
+		if typos <= 0 || typos >= 1 {
+			return matcher, fmt.Errorf("the typo rate must be greater than 0 and less than 1")
+		}
+		matcher.TypoRate = typos
+	}
+	matcher.Expressions = expressions
+	return matcher, nil
+}
+
+// displayOptions returns the session options that show the time left and warn when
+// it runs low. The clock is live when standard output is a terminal, and plain text
+// otherwise so that redirected output stays readable.
+func displayOptions(clock bool, warn string) ([]quiz.SessionOption, error) {
+	var opts []quiz.SessionOption
+	if clock {
+		mode := quiz.PlainClock
+		if isTerminal(os.Stdout) {
+			mode = quiz.LiveClock
+		}
+		opts = append(opts, quiz.WithClock(mode))
+	}
+	var thresholds []time.Duration
+	for _, field := range strings.Split(warn, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		secs, err := strconv.Atoi(field)
+		if err != nil || secs <= 0 {
+			return nil, fmt.Errorf("invalid warning threshold %q: use a positive number of seconds", field)
+		}
+		thresholds = append(thresholds, time.Duration(secs)*time.Second)
+	}
+	if len(thresholds) > 0 {
+		opts = append(opts, quiz.WithWarnings(thresholds...))
+	}
+	return opts, nil
+}
+
+// isTerminal reports whether f is a terminal rather than a file or a pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// quizLanguage returns the language of the quiz messages: code if set, otherwise the
+// system locale if supported, otherwise English.
+func quizLanguage(code string) (language.Tag, error) {
+	if code != "" {
+		return quiz.ParseLanguage(code)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			tag, _ := quiz.ParseLanguage(locale) // English for unsupported locales
+			return tag, nil
+		}
+	}
+	return language.English, nil
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// host implements the "host" subcommand, which serves a multiplayer room for a
+// question bank and starts the quiz when the host presses Enter.
+func host(args []string) {
+	fs := flag.NewFlagSet("host", flag.ExitOnError)
+	csvPtr := fs.String("csv", "Problems.csv", "a question bank file")
+	formatPtr := fs.String("format", "", "the question bank format (csv, json, yaml, md, gift or apkg). Defaults to the file extension")
+	ankiFieldsPtr := fs.String("anki-fields", "1,2", "the note fields of an Anki deck used as question and answer, counting from 1")
+	addrPtr := fs.String("addr", ":8080", "the address to serve the room on")
+	secondsPtr := fs.Int("seconds", 20, "the time limit for each question in seconds")
+	matchPtr := fs.String("match", "exact", "how answers are compared: exact, normalized (ignoring case, accents and punctuation) or fuzzy (normalized, tolerating typos)")
+	typosPtr := fs.Float64("typos", quiz.DefaultTypoRate, "the typos tolerated per character of the answer with -match=fuzzy")
+	expressionsPtr := fs.Bool("expressions", false, "accept any arithmetic expression that evaluates to a numeric answer, e.g. '2*5' for 10")
+	fs.Parse(args)
+
+	matcher, err := answerMatcher(*matchPtr, *typosPtr, *expressionsPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	anki, err := ankiFields(*ankiFieldsPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	problems, _, err := quiz.LoadFile(*csvPtr, *formatPtr, anki)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := quiz.NewServer()
+	room := srv.NewRoom(problems, time.Duration(*secondsPtr)*time.Second, os.Stdout, quiz.WithRoomMatcher(matcher))
+
+	ln, err := net.Listen("tcp", *addrPtr)
+	if err != nil {
+		log.Fatalf("Couldn't listen on %s: %v", *addrPtr, err)
+	}
+	go func() {
+		log.Fatal(http.Serve(ln, srv))
+	}()
+
+	fmt.Printf("Room code: %s\n", room.Code)
+	fmt.Printf("Players join with: quiz join -server ws://<this host>%s -code %s -name <name>\n", *addrPtr, room.Code)
+	fmt.Println("Press Enter to start once everyone has joined.")
+	bufio.NewScanner(os.Stdin).Scan()
+
+	if len(room.Players()) == 0 {
+		log.Fatal("Nobody joined the room.")
+	}
+	room.Run(context.Background())
+}
+
+// join implements the "join" subcommand, which takes part in a multiplayer room.
+func join(args []string) {
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	serverPtr := fs.String("server", "ws://localhost:8080", "the address of the quiz host")
+	codePtr := fs.String("code", "", "the room code shown by the host")
+	namePtr := fs.String("name", os.Getenv("USER"), "your player name")
+	fs.Parse(args)
+
+	if *codePtr == "" {
+		log.Fatal("No room code provided. Use -code to specify the room.")
+	}
+
+	ctx := context.Background()
+	p, err := quiz.JoinRoom(ctx, *serverPtr, *codePtr, *namePtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	if _, err := p.Play(ctx, os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
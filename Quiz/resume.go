@@ -0,0 +1,34 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"context"
+	"log"
+	"os"
+)
+
+// resume continues the session saved at path, with the question order, answers and
+// remaining time it was saved with. A finished session is recorded on the leaderboard
+// database db (unless empty) and its save file is removed. The options set how the
+// time left is shown.
+func resume(path, player, db string, opts ...quiz.SessionOption) {
+	s, err := quiz.ResumeSession(path, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	res := s.Run(context.Background())
+	if res.Saved {
+		return
+	}
+
+	// The session is over, so it can't be resumed again
+	if err := os.Remove(path); err != nil {
+		log.Printf("Failed to remove the session file %s: %v", path, err)
+	}
+	if db != "" {
+		name, hash := s.Quiz()
+		if err := recordResult(db, player, name, hash, res); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	quiz "Quiz/QuizLogic"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+// quizStats holds the item analysis of one quiz for JSON output.
+type quizStats struct {
+	Name  string          `json:"name"`
+	Hash  string          `json:"hash"`
+	Items []quiz.ItemStat `json:"items"`
+}
+
+// stats implements the "stats" subcommand, which prints the item analysis of every
+// recorded quiz (or a single one): how each question performed across all runs.
+func stats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	dbPtr := fs.String("db", "quiz.db", "the SQLite database runs are recorded in")
+	csvPtr := fs.String("csv", "", "only analyze the runs of this question bank file")
+	jsonPtr := fs.Bool("json", false, "print the statistics as JSON instead of a table")
+	fs.Parse(args)
+
+	db, err := sql.Open("sqlite", *dbPtr)
+	if err != nil {
+		log.Fatalf("Couldn't open SQLite database file %s: %v", *dbPtr, err)
+	}
+	defer db.Close()
+
+	lb, err := quiz.NewLeaderboard(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Determine which quizzes to analyze
+	var quizzes []quiz.QuizSummary
+	if *csvPtr != "" {
+		data, err := quiz.ReadSource(*csvPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		quizzes = []quiz.QuizSummary{{Hash: quiz.HashQuiz(data), Name: *csvPtr}}
+	} else if quizzes, err = lb.Quizzes(); err != nil {
+		log.Fatal(err)
+	}
+
+	all := make([]quizStats, 0, len(quizzes))
+	for _, q := range quizzes {
+		items, err := lb.ItemStats(q.Hash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(items) > 0 {
+			all = append(all, quizStats{Name: q.Name, Hash: q.Hash, Items: items})
+		}
+	}
+
+	if *jsonPtr {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(all) == 0 {
+		fmt.Println("No answers recorded yet.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, q := range all {
+		fmt.Fprintf(w, "\n== %s (%s) ==\n", q.Name, q.Hash)
+		fmt.Fprintln(w, "QUESTION\tSEEN\tCORRECT\tMEAN TIME\tSKIPPED\tTIMED OUT\tDISCRIM")
+		for _, it := range q.Items {
+			discrim := "-"
+			if it.Discrimination != nil {
+				discrim = fmt.Sprintf("%+.2f", *it.Discrimination)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%.0f%%\t%.1fs\t%d\t%d\t%s\n", it.Question, it.Seen, it.PercentCorrect, it.MeanSeconds, it.Skipped, it.TimedOut, discrim)
+		}
+	}
+	w.Flush()
+}